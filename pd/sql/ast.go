@@ -0,0 +1,105 @@
+package sql
+
+// expr sql表达式
+type expr interface{}
+
+type literalExpr struct {
+	val value
+}
+
+type columnExpr struct {
+	table string
+	name  string
+}
+
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+type binaryExpr struct {
+	op    string
+	left  expr
+	right expr
+}
+
+type funcExpr struct {
+	name     string
+	args     []expr
+	star     bool
+	distinct bool
+}
+
+type inExpr struct {
+	x    expr
+	list []expr
+	not  bool
+}
+
+type betweenExpr struct {
+	x    expr
+	low  expr
+	high expr
+	not  bool
+}
+
+type isNullExpr struct {
+	x   expr
+	not bool
+}
+
+type likeExpr struct {
+	x       expr
+	pattern expr
+	not     bool
+}
+
+type caseExpr struct {
+	operand  expr
+	whens    []caseWhen
+	elseExpr expr
+}
+
+type caseWhen struct {
+	cond   expr
+	result expr
+}
+
+type selectStmt struct {
+	distinct bool
+	items    []selectItem
+	from     tableRef
+	joins    []joinClause
+	where    expr
+	groupBy  []expr
+	having   expr
+	orderBy  []orderItem
+	limit    int
+	offset   int
+}
+
+type selectItem struct {
+	expr  expr
+	alias string
+	// text 表达式的原文，没有别名时作为列名
+	text string
+	// star 为true时表示 * 或 table.*，starTable为空表示所有表
+	star      bool
+	starTable string
+}
+
+type tableRef struct {
+	name  string
+	alias string
+}
+
+type joinClause struct {
+	left  bool
+	table tableRef
+	on    expr
+}
+
+type orderItem struct {
+	expr expr
+	desc bool
+}
@@ -0,0 +1,540 @@
+package sql
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var aggregateFuncs = map[string]bool{
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true,
+}
+
+// containsAggregate 判断表达式中是否包含聚合函数
+func containsAggregate(e expr) bool {
+	switch e := e.(type) {
+	case *funcExpr:
+		if aggregateFuncs[e.name] {
+			return true
+		}
+		for _, arg := range e.args {
+			if containsAggregate(arg) {
+				return true
+			}
+		}
+	case *unaryExpr:
+		return containsAggregate(e.x)
+	case *binaryExpr:
+		return containsAggregate(e.left) || containsAggregate(e.right)
+	case *inExpr:
+		if containsAggregate(e.x) {
+			return true
+		}
+		for _, item := range e.list {
+			if containsAggregate(item) {
+				return true
+			}
+		}
+	case *betweenExpr:
+		return containsAggregate(e.x) || containsAggregate(e.low) || containsAggregate(e.high)
+	case *isNullExpr:
+		return containsAggregate(e.x)
+	case *likeExpr:
+		return containsAggregate(e.x) || containsAggregate(e.pattern)
+	case *caseExpr:
+		if e.operand != nil && containsAggregate(e.operand) {
+			return true
+		}
+		for _, when := range e.whens {
+			if containsAggregate(when.cond) || containsAggregate(when.result) {
+				return true
+			}
+		}
+		return e.elseExpr != nil && containsAggregate(e.elseExpr)
+	}
+	return false
+}
+
+func (ex *executor) eval(e expr, en *env) (value, error) {
+	switch e := e.(type) {
+	case *literalExpr:
+		return e.val, nil
+
+	case *columnExpr:
+		i, err := en.scope.resolve(e)
+		if err != nil {
+			return nullValue, err
+		}
+		return en.row[i], nil
+
+	case *unaryExpr:
+		x, err := ex.eval(e.x, en)
+		if err != nil || x.null {
+			return nullValue, err
+		}
+		if e.op == "NOT" {
+			return boolValue(!x.truth()), nil
+		}
+		f, _, err := x.mustNumber()
+		if err != nil {
+			return nullValue, err
+		}
+		return numberValue(-f), nil
+
+	case *binaryExpr:
+		return ex.evalBinary(e, en)
+
+	case *isNullExpr:
+		x, err := ex.eval(e.x, en)
+		if err != nil {
+			return nullValue, err
+		}
+		return boolValue(x.null != e.not), nil
+
+	case *inExpr:
+		x, err := ex.eval(e.x, en)
+		if err != nil || x.null {
+			return nullValue, err
+		}
+		hasNull := false
+		for _, item := range e.list {
+			v, err := ex.eval(item, en)
+			if err != nil {
+				return nullValue, err
+			}
+			if v.null {
+				hasNull = true
+				continue
+			}
+			if compare(x, v) == 0 {
+				return boolValue(!e.not), nil
+			}
+		}
+		if hasNull {
+			return nullValue, nil
+		}
+		return boolValue(e.not), nil
+
+	case *betweenExpr:
+		x, err := ex.eval(e.x, en)
+		if err != nil {
+			return nullValue, err
+		}
+		low, err := ex.eval(e.low, en)
+		if err != nil {
+			return nullValue, err
+		}
+		high, err := ex.eval(e.high, en)
+		if err != nil {
+			return nullValue, err
+		}
+		if x.null || low.null || high.null {
+			return nullValue, nil
+		}
+		in := compareForSort(x, low) >= 0 && compareForSort(x, high) <= 0
+		return boolValue(in != e.not), nil
+
+	case *likeExpr:
+		x, err := ex.eval(e.x, en)
+		if err != nil {
+			return nullValue, err
+		}
+		pattern, err := ex.eval(e.pattern, en)
+		if err != nil {
+			return nullValue, err
+		}
+		if x.null || pattern.null {
+			return nullValue, nil
+		}
+		return boolValue(ex.likeRegexp(pattern.str).MatchString(x.str) != e.not), nil
+
+	case *caseExpr:
+		return ex.evalCase(e, en)
+
+	case *funcExpr:
+		if aggregateFuncs[e.name] {
+			return ex.evalAggregate(e, en)
+		}
+		return ex.evalFunc(e, en)
+
+	default:
+		return nullValue, fmt.Errorf("sql: unsupported expression %T", e)
+	}
+}
+
+func (ex *executor) evalBinary(e *binaryExpr, en *env) (value, error) {
+	left, err := ex.eval(e.left, en)
+	if err != nil {
+		return nullValue, err
+	}
+
+	// AND、OR使用三值逻辑，并在结果确定时短路
+	switch e.op {
+	case "AND":
+		if !left.null && !left.truth() {
+			return boolValue(false), nil
+		}
+		right, err := ex.eval(e.right, en)
+		if err != nil {
+			return nullValue, err
+		}
+		if !right.null && !right.truth() {
+			return boolValue(false), nil
+		}
+		if left.null || right.null {
+			return nullValue, nil
+		}
+		return boolValue(true), nil
+
+	case "OR":
+		if left.truth() {
+			return boolValue(true), nil
+		}
+		right, err := ex.eval(e.right, en)
+		if err != nil {
+			return nullValue, err
+		}
+		if right.truth() {
+			return boolValue(true), nil
+		}
+		if left.null || right.null {
+			return nullValue, nil
+		}
+		return boolValue(false), nil
+	}
+
+	right, err := ex.eval(e.right, en)
+	if err != nil {
+		return nullValue, err
+	}
+	if left.null || right.null {
+		return nullValue, nil
+	}
+
+	switch e.op {
+	case "=":
+		return boolValue(compare(left, right) == 0), nil
+	case "!=":
+		return boolValue(compare(left, right) != 0), nil
+	case "<":
+		return boolValue(compareForSort(left, right) < 0), nil
+	case "<=":
+		return boolValue(compareForSort(left, right) <= 0), nil
+	case ">":
+		return boolValue(compareForSort(left, right) > 0), nil
+	case ">=":
+		return boolValue(compareForSort(left, right) >= 0), nil
+	case "||":
+		return stringValue(left.str + right.str), nil
+	}
+
+	l, _, err := left.mustNumber()
+	if err != nil {
+		return nullValue, err
+	}
+	r, _, err := right.mustNumber()
+	if err != nil {
+		return nullValue, err
+	}
+
+	switch e.op {
+	case "+":
+		return numberValue(l + r), nil
+	case "-":
+		return numberValue(l - r), nil
+	case "*":
+		return numberValue(l * r), nil
+	case "/":
+		if r == 0 {
+			return nullValue, nil
+		}
+		return numberValue(l / r), nil
+	case "%":
+		if r == 0 {
+			return nullValue, nil
+		}
+		return numberValue(math.Mod(l, r)), nil
+	default:
+		return nullValue, fmt.Errorf("sql: unsupported operator %s", e.op)
+	}
+}
+
+func (ex *executor) evalCase(e *caseExpr, en *env) (value, error) {
+	var operand value
+	if e.operand != nil {
+		var err error
+		if operand, err = ex.eval(e.operand, en); err != nil {
+			return nullValue, err
+		}
+	}
+
+	for _, when := range e.whens {
+		cond, err := ex.eval(when.cond, en)
+		if err != nil {
+			return nullValue, err
+		}
+
+		matched := cond.truth()
+		if e.operand != nil {
+			matched = !operand.null && !cond.null && compare(operand, cond) == 0
+		}
+		if matched {
+			return ex.eval(when.result, en)
+		}
+	}
+
+	if e.elseExpr != nil {
+		return ex.eval(e.elseExpr, en)
+	}
+	return nullValue, nil
+}
+
+// evalAggregate 计算聚合函数，忽略NULL；除COUNT外，没有非NULL值时返回NULL
+func (ex *executor) evalAggregate(e *funcExpr, en *env) (value, error) {
+	if !en.grouped {
+		return nullValue, fmt.Errorf("sql: aggregate function %s is not allowed here", e.name)
+	}
+	if e.star {
+		return numberValue(float64(len(en.group))), nil
+	}
+	if len(e.args) != 1 {
+		return nullValue, fmt.Errorf("sql: %s expects 1 argument", e.name)
+	}
+	if containsAggregate(e.args[0]) {
+		return nullValue, fmt.Errorf("sql: nested aggregate function in %s", e.name)
+	}
+
+	var values []value
+	seen := map[string]bool{}
+	for _, row := range en.group {
+		v, err := ex.eval(e.args[0], &env{scope: en.scope, row: row})
+		if err != nil {
+			return nullValue, err
+		}
+		if v.null {
+			continue
+		}
+		if e.distinct {
+			if seen[v.key()] {
+				continue
+			}
+			seen[v.key()] = true
+		}
+		values = append(values, v)
+	}
+
+	if e.name == "COUNT" {
+		return numberValue(float64(len(values))), nil
+	}
+	if len(values) == 0 {
+		return nullValue, nil
+	}
+
+	switch e.name {
+	case "MIN", "MAX":
+		result := values[0]
+		for _, v := range values[1:] {
+			cmp := compareForSort(v, result)
+			if (e.name == "MIN" && cmp < 0) || (e.name == "MAX" && cmp > 0) {
+				result = v
+			}
+		}
+		return result, nil
+
+	default:
+		sum := 0.0
+		for _, v := range values {
+			f, _, err := v.mustNumber()
+			if err != nil {
+				return nullValue, err
+			}
+			sum += f
+		}
+		if e.name == "AVG" {
+			return numberValue(sum / float64(len(values))), nil
+		}
+		return numberValue(sum), nil
+	}
+}
+
+func (ex *executor) evalFunc(e *funcExpr, en *env) (value, error) {
+	if e.distinct {
+		return nullValue, fmt.Errorf("sql: DISTINCT is only allowed in aggregate functions")
+	}
+
+	args := make([]value, len(e.args))
+	for i, arg := range e.args {
+		v, err := ex.eval(arg, en)
+		if err != nil {
+			return nullValue, err
+		}
+		args[i] = v
+	}
+
+	argCount := func(min, max int) error {
+		if len(args) < min || (max >= 0 && len(args) > max) {
+			return fmt.Errorf("sql: wrong number of arguments to %s", e.name)
+		}
+		return nil
+	}
+
+	switch e.name {
+	case "COALESCE", "IFNULL":
+		if err := argCount(1, -1); err != nil {
+			return nullValue, err
+		}
+		for _, arg := range args {
+			if !arg.null {
+				return arg, nil
+			}
+		}
+		return nullValue, nil
+
+	case "NULLIF":
+		if err := argCount(2, 2); err != nil {
+			return nullValue, err
+		}
+		if !args[0].null && !args[1].null && compare(args[0], args[1]) == 0 {
+			return nullValue, nil
+		}
+		return args[0], nil
+
+	case "CONCAT":
+		var sb strings.Builder
+		for _, arg := range args {
+			sb.WriteString(arg.str)
+		}
+		return stringValue(sb.String()), nil
+	}
+
+	for _, arg := range args {
+		if arg.null {
+			return nullValue, nil
+		}
+	}
+
+	switch e.name {
+	case "UPPER":
+		if err := argCount(1, 1); err != nil {
+			return nullValue, err
+		}
+		return stringValue(strings.ToUpper(args[0].str)), nil
+
+	case "LOWER":
+		if err := argCount(1, 1); err != nil {
+			return nullValue, err
+		}
+		return stringValue(strings.ToLower(args[0].str)), nil
+
+	case "LENGTH":
+		if err := argCount(1, 1); err != nil {
+			return nullValue, err
+		}
+		return numberValue(float64(utf8.RuneCountInString(args[0].str))), nil
+
+	case "TRIM", "LTRIM", "RTRIM":
+		if err := argCount(1, 1); err != nil {
+			return nullValue, err
+		}
+		switch e.name {
+		case "LTRIM":
+			return stringValue(strings.TrimLeft(args[0].str, " \t\r\n")), nil
+		case "RTRIM":
+			return stringValue(strings.TrimRight(args[0].str, " \t\r\n")), nil
+		default:
+			return stringValue(strings.TrimSpace(args[0].str)), nil
+		}
+
+	case "REPLACE":
+		if err := argCount(3, 3); err != nil {
+			return nullValue, err
+		}
+		return stringValue(strings.ReplaceAll(args[0].str, args[1].str, args[2].str)), nil
+
+	case "SUBSTR", "SUBSTRING":
+		if err := argCount(2, 3); err != nil {
+			return nullValue, err
+		}
+		runes := []rune(args[0].str)
+		start, _, err := args[1].mustNumber()
+		if err != nil {
+			return nullValue, err
+		}
+		// 与sql一致，起始位置从1开始
+		from := int(start) - 1
+		if from < 0 {
+			from = 0
+		}
+		to := len(runes)
+		if len(args) == 3 {
+			length, _, err := args[2].mustNumber()
+			if err != nil {
+				return nullValue, err
+			}
+			if from+int(length) < to {
+				to = from + int(length)
+			}
+		}
+		if from >= to {
+			return stringValue(""), nil
+		}
+		return stringValue(string(runes[from:to])), nil
+
+	case "ABS":
+		if err := argCount(1, 1); err != nil {
+			return nullValue, err
+		}
+		f, _, err := args[0].mustNumber()
+		if err != nil {
+			return nullValue, err
+		}
+		return numberValue(math.Abs(f)), nil
+
+	case "ROUND":
+		if err := argCount(1, 2); err != nil {
+			return nullValue, err
+		}
+		f, _, err := args[0].mustNumber()
+		if err != nil {
+			return nullValue, err
+		}
+		digits := 0.0
+		if len(args) == 2 {
+			if digits, _, err = args[1].mustNumber(); err != nil {
+				return nullValue, err
+			}
+		}
+		pow := math.Pow(10, digits)
+		return numberValue(math.Round(f*pow) / pow), nil
+
+	default:
+		return nullValue, fmt.Errorf("sql: unsupported function %s", e.name)
+	}
+}
+
+// likeRegexp 将LIKE的模式转换为正则，%匹配任意个字符，_匹配一个字符，不区分大小写
+func (ex *executor) likeRegexp(pattern string) *regexp.Regexp {
+	if re, ok := ex.likeCache[pattern]; ok {
+		return re
+	}
+
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+
+	re := regexp.MustCompile(sb.String())
+	ex.likeCache[pattern] = re
+	return re
+}
@@ -0,0 +1,454 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pd "github.com/wuyyyyyou/go-share/pd/v3"
+)
+
+type column struct {
+	table string
+	name  string
+}
+
+// scope 当前可以引用的所有列，行中的值与列一一对应
+type scope struct {
+	columns []column
+}
+
+// resolve 查找列的位置，列名先精确匹配，找不到时不区分大小写再匹配一次
+func (s *scope) resolve(c *columnExpr) (int, error) {
+	for _, exact := range []bool{true, false} {
+		found := -1
+		for i, col := range s.columns {
+			if c.table != "" && !strings.EqualFold(col.table, c.table) {
+				continue
+			}
+			if (exact && col.name == c.name) || (!exact && strings.EqualFold(col.name, c.name)) {
+				if found >= 0 {
+					return 0, fmt.Errorf("sql: column %s is ambiguous", c.name)
+				}
+				found = i
+			}
+		}
+		if found >= 0 {
+			return found, nil
+		}
+	}
+
+	if c.table != "" {
+		return 0, fmt.Errorf("sql: cannot find column %s.%s", c.table, c.name)
+	}
+	return 0, fmt.Errorf("sql: cannot find column %s", c.name)
+}
+
+type env struct {
+	scope *scope
+	row   []value
+	// grouped 为true时可以使用聚合函数，group为当前分组的所有行
+	grouped bool
+	group   [][]value
+}
+
+type executor struct {
+	db        *DB
+	likeCache map[string]*regexp.Regexp
+}
+
+func newExecutor(db *DB) *executor {
+	return &executor{db: db, likeCache: map[string]*regexp.Regexp{}}
+}
+
+type output struct {
+	expr expr
+	name string
+}
+
+type resultRow struct {
+	values []value
+	keys   []value
+}
+
+func (ex *executor) execute(stmt *selectStmt) (*pd.DataFrame, error) {
+	sc, rows, err := ex.loadTable(stmt.from)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, join := range stmt.joins {
+		if sc, rows, err = ex.join(sc, rows, join); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.where != nil {
+		if containsAggregate(stmt.where) {
+			return nil, fmt.Errorf("sql: aggregate functions are not allowed in WHERE")
+		}
+		filtered := rows[:0:0]
+		for _, row := range rows {
+			v, err := ex.eval(stmt.where, &env{scope: sc, row: row})
+			if err != nil {
+				return nil, err
+			}
+			if v.truth() {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+
+	outputs, err := expandOutputs(stmt.items, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := len(stmt.groupBy) > 0 || stmt.having != nil
+	for _, out := range outputs {
+		grouped = grouped || containsAggregate(out.expr)
+	}
+	for _, item := range stmt.orderBy {
+		grouped = grouped || containsAggregate(item.expr)
+	}
+
+	var envs []*env
+	if grouped {
+		if envs, err = ex.groupRows(sc, rows, stmt.groupBy); err != nil {
+			return nil, err
+		}
+	} else {
+		envs = make([]*env, len(rows))
+		for i, row := range rows {
+			envs[i] = &env{scope: sc, row: row}
+		}
+	}
+
+	var results []resultRow
+	seen := map[string]bool{}
+	for _, e := range envs {
+		if stmt.having != nil {
+			v, err := ex.eval(stmt.having, e)
+			if err != nil {
+				return nil, err
+			}
+			if !v.truth() {
+				continue
+			}
+		}
+
+		values := make([]value, len(outputs))
+		for i, out := range outputs {
+			if values[i], err = ex.eval(out.expr, e); err != nil {
+				return nil, err
+			}
+		}
+
+		if stmt.distinct {
+			key := rowKey(values)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		keys := make([]value, len(stmt.orderBy))
+		for i, item := range stmt.orderBy {
+			if keys[i], err = ex.orderKey(item.expr, e, outputs, values); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, resultRow{values: values, keys: keys})
+	}
+
+	if len(stmt.orderBy) > 0 {
+		sort.SliceStable(results, func(a, b int) bool {
+			for i, item := range stmt.orderBy {
+				cmp := compareForSort(results[a].keys[i], results[b].keys[i])
+				if cmp == 0 {
+					continue
+				}
+				if item.desc {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+	}
+
+	if stmt.offset > 0 {
+		if stmt.offset >= len(results) {
+			results = nil
+		} else {
+			results = results[stmt.offset:]
+		}
+	}
+	if stmt.limit >= 0 && stmt.limit < len(results) {
+		results = results[:stmt.limit]
+	}
+
+	df := pd.NewDataFrame("")
+	heads := make([]string, len(outputs))
+	used := map[string]bool{}
+	for i, out := range outputs {
+		heads[i] = out.name
+		for n := 2; used[heads[i]]; n++ {
+			heads[i] = out.name + "_" + strconv.Itoa(n)
+		}
+		used[heads[i]] = true
+	}
+	df.SetHeads(heads)
+
+	dfRows := make([][]string, len(results))
+	for i, result := range results {
+		row := make([]string, len(result.values))
+		for j, v := range result.values {
+			if !v.null {
+				row[j] = v.str
+			}
+		}
+		dfRows[i] = row
+	}
+	df.SetRows(dfRows)
+	return df, nil
+}
+
+// loadTable 将df转换为行，短于表头的行用NULL补齐
+func (ex *executor) loadTable(ref tableRef) (*scope, [][]value, error) {
+	df, err := ex.db.table(ref.name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	heads := df.GetHeads()
+	sc := &scope{columns: make([]column, len(heads))}
+	for i, head := range heads {
+		sc.columns[i] = column{table: ref.alias, name: head}
+	}
+
+	rows := make([][]value, df.GetLength())
+	for i, record := range df.GetRows() {
+		row := make([]value, len(heads))
+		for j := range heads {
+			if j < len(record) {
+				row[j] = cellValue(record[j])
+			} else {
+				row[j] = nullValue
+			}
+		}
+		rows[i] = row
+	}
+	return sc, rows, nil
+}
+
+// join ON条件为两个表的列相等时使用哈希连接，否则使用嵌套循环
+func (ex *executor) join(left *scope, leftRows [][]value, join joinClause) (*scope, [][]value, error) {
+	right, rightRows, err := ex.loadTable(join.table)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, col := range left.columns {
+		if strings.EqualFold(col.table, join.table.alias) {
+			return nil, nil, fmt.Errorf("sql: table alias %s is used more than once", join.table.alias)
+		}
+	}
+
+	sc := &scope{columns: append(append([]column{}, left.columns...), right.columns...)}
+	nullRight := make([]value, len(right.columns))
+	for i := range nullRight {
+		nullRight[i] = nullValue
+	}
+	combine := func(l, r []value) []value {
+		return append(append(make([]value, 0, len(l)+len(r)), l...), r...)
+	}
+
+	var rows [][]value
+	if leftPos, rightPos, ok := equiJoinColumns(join.on, left, right); ok {
+		hash := map[string][]int{}
+		for i, row := range rightRows {
+			if !row[rightPos].null {
+				key := row[rightPos].key()
+				hash[key] = append(hash[key], i)
+			}
+		}
+		for _, l := range leftRows {
+			var matches []int
+			if !l[leftPos].null {
+				matches = hash[l[leftPos].key()]
+			}
+			for _, i := range matches {
+				rows = append(rows, combine(l, rightRows[i]))
+			}
+			if len(matches) == 0 && join.left {
+				rows = append(rows, combine(l, nullRight))
+			}
+		}
+		return sc, rows, nil
+	}
+
+	for _, l := range leftRows {
+		matched := false
+		for _, r := range rightRows {
+			row := combine(l, r)
+			v, err := ex.eval(join.on, &env{scope: sc, row: row})
+			if err != nil {
+				return nil, nil, err
+			}
+			if v.truth() {
+				rows = append(rows, row)
+				matched = true
+			}
+		}
+		if !matched && join.left {
+			rows = append(rows, combine(l, nullRight))
+		}
+	}
+	return sc, rows, nil
+}
+
+// equiJoinColumns 判断ON条件是否为左右两个表的列相等，返回两列分别在左右表中的位置
+func equiJoinColumns(on expr, left, right *scope) (int, int, bool) {
+	b, ok := on.(*binaryExpr)
+	if !ok || b.op != "=" {
+		return 0, 0, false
+	}
+	a, aOk := b.left.(*columnExpr)
+	c, cOk := b.right.(*columnExpr)
+	if !aOk || !cOk {
+		return 0, 0, false
+	}
+
+	aLeft, aLeftErr := left.resolve(a)
+	aRight, aRightErr := right.resolve(a)
+	cLeft, cLeftErr := left.resolve(c)
+	cRight, cRightErr := right.resolve(c)
+	switch {
+	case aLeftErr == nil && aRightErr != nil && cRightErr == nil && cLeftErr != nil:
+		return aLeft, cRight, true
+	case cLeftErr == nil && cRightErr != nil && aRightErr == nil && aLeftErr != nil:
+		return cLeft, aRight, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// groupRows 按GROUP BY分组，分组按第一次出现的顺序排列；没有GROUP BY时所有行为一组，即使没有行也会返回一组
+func (ex *executor) groupRows(sc *scope, rows [][]value, groupBy []expr) ([]*env, error) {
+	for _, e := range groupBy {
+		if containsAggregate(e) {
+			return nil, fmt.Errorf("sql: aggregate functions are not allowed in GROUP BY")
+		}
+	}
+
+	if len(groupBy) == 0 {
+		row := make([]value, len(sc.columns))
+		if len(rows) > 0 {
+			row = rows[0]
+		} else {
+			for i := range row {
+				row[i] = nullValue
+			}
+		}
+		return []*env{{scope: sc, row: row, grouped: true, group: rows}}, nil
+	}
+
+	var envs []*env
+	groups := map[string]*env{}
+	for _, row := range rows {
+		keys := make([]value, len(groupBy))
+		for i, e := range groupBy {
+			v, err := ex.eval(e, &env{scope: sc, row: row})
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = v
+		}
+
+		key := rowKey(keys)
+		g, ok := groups[key]
+		if !ok {
+			g = &env{scope: sc, row: row, grouped: true}
+			groups[key] = g
+			envs = append(envs, g)
+		}
+		g.group = append(g.group, row)
+	}
+	return envs, nil
+}
+
+// expandOutputs 展开 * 和 table.*，确定结果中每一列的名称
+func expandOutputs(items []selectItem, sc *scope) ([]output, error) {
+	nameCount := map[string]int{}
+	for _, col := range sc.columns {
+		nameCount[col.name]++
+	}
+
+	var outputs []output
+	for _, item := range items {
+		if !item.star {
+			name := item.alias
+			if name == "" {
+				if c, ok := item.expr.(*columnExpr); ok {
+					name = c.name
+				} else {
+					name = item.text
+				}
+			}
+			outputs = append(outputs, output{expr: item.expr, name: name})
+			continue
+		}
+
+		found := false
+		for _, col := range sc.columns {
+			if item.starTable != "" && !strings.EqualFold(col.table, item.starTable) {
+				continue
+			}
+			found = true
+			name := col.name
+			if nameCount[col.name] > 1 && item.starTable == "" {
+				name = col.table + "." + col.name
+			}
+			outputs = append(outputs, output{expr: &columnExpr{table: col.table, name: col.name}, name: name})
+		}
+		if !found && item.starTable != "" {
+			return nil, fmt.Errorf("sql: cannot find table %s", item.starTable)
+		}
+	}
+	return outputs, nil
+}
+
+// orderKey ORDER BY中可以使用结果列的别名或从1开始的列序号
+func (ex *executor) orderKey(e expr, en *env, outputs []output, values []value) (value, error) {
+	switch e := e.(type) {
+	case *literalExpr:
+		if n, err := strconv.Atoi(e.val.str); err == nil {
+			if n < 1 || n > len(outputs) {
+				return nullValue, fmt.Errorf("sql: ORDER BY position %d out of range", n)
+			}
+			return values[n-1], nil
+		}
+	case *columnExpr:
+		if e.table == "" {
+			if _, err := en.scope.resolve(e); err != nil {
+				for i, out := range outputs {
+					if strings.EqualFold(out.name, e.name) {
+						return values[i], nil
+					}
+				}
+			}
+		}
+	}
+	return ex.eval(e, en)
+}
+
+func rowKey(values []value) string {
+	keys := make([]string, len(values))
+	for i, v := range values {
+		keys[i] = v.key()
+	}
+	return strings.Join(keys, "\x1F")
+}
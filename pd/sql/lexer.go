@@ -0,0 +1,142 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+}
+
+// isKeyword 判断未加引号的标识符是否为指定关键字，不区分大小写
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+// tokenize 将sql拆分为token，标识符可以用双引号、反引号或方括号包裹，字符串使用单引号，两个单引号表示一个单引号
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	// offsets 记录每个rune在原字符串中的字节位置，用于截取表达式原文
+	offsets := make([]int, len(runes)+1)
+	offset := 0
+	for i, r := range runes {
+		offsets[i] = offset
+		offset += len(string(r))
+	}
+	offsets[len(runes)] = offset
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			continue
+
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i])})
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i])})
+
+		case r == '\'':
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string at position %d", offsets[start])
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						sb.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: sb.String()})
+
+		case r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			i++
+			for i < len(runes) && runes[i] != closing {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated identifier at position %d", offsets[start])
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: string(runes[start+1 : i-1])})
+
+		default:
+			symbol := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>", "!=", "||":
+					symbol = two
+				}
+			}
+			if !strings.Contains("(),.*+-/%=<>!|;", string(r)) {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, offsets[start])
+			}
+			i += len([]rune(symbol))
+			tokens = append(tokens, token{kind: tokenSymbol, text: symbol})
+		}
+
+		tokens[len(tokens)-1].start = offsets[start]
+		tokens[len(tokens)-1].end = offsets[i]
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, start: len(query), end: len(query)})
+	return tokens, nil
+}
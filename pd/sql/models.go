@@ -0,0 +1,16 @@
+package sql
+
+import (
+	pd "github.com/wuyyyyyou/go-share/pd/v3"
+)
+
+// DB 内存中的表集合，每个表对应一个df，不需要外部数据库
+type DB struct {
+	tables map[string]*pd.DataFrame
+}
+
+func NewDB() *DB {
+	return &DB{
+		tables: map[string]*pd.DataFrame{},
+	}
+}
@@ -0,0 +1,616 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// reservedWords 不能作为省略AS的别名使用的关键字
+var reservedWords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "FROM": true, "WHERE": true, "GROUP": true, "BY": true,
+	"HAVING": true, "ORDER": true, "ASC": true, "DESC": true, "LIMIT": true, "OFFSET": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "OUTER": true, "ON": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "NULL": true, "LIKE": true,
+	"BETWEEN": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
+}
+
+type parser struct {
+	query  string
+	tokens []token
+	pos    int
+}
+
+func parse(query string) (*selectStmt, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{query: query, tokens: tokens}
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+
+	p.acceptSymbol(";")
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return stmt, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("sql: "+format+" at position %d", append(args, p.peek().start)...)
+}
+
+func (p *parser) acceptKeyword(keyword string) bool {
+	if p.peek().isKeyword(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.errorf("expected %s", keyword)
+	}
+	return nil
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if p.peek().isSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.errorf("expected %q", symbol)
+	}
+	return nil
+}
+
+// parseIdent 解析标识符，未加引号的保留字不能作为标识符
+func (p *parser) parseIdent() (string, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenQuotedIdent:
+		p.pos++
+		return t.text, nil
+	case t.kind == tokenIdent && !reservedWords[strings.ToUpper(t.text)]:
+		p.pos++
+		return t.text, nil
+	default:
+		return "", p.errorf("expected identifier")
+	}
+}
+
+// parseAlias 解析可选的别名，AS可以省略
+func (p *parser) parseAlias() (string, error) {
+	if p.acceptKeyword("AS") {
+		return p.parseIdent()
+	}
+	t := p.peek()
+	if t.kind == tokenQuotedIdent || (t.kind == tokenIdent && !reservedWords[strings.ToUpper(t.text)]) {
+		return p.parseIdent()
+	}
+	return "", nil
+}
+
+func (p *parser) parseSelect() (*selectStmt, error) {
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	stmt := &selectStmt{limit: -1}
+	stmt.distinct = p.acceptKeyword("DISTINCT")
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.items = append(stmt.items, item)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	from, err := p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+	stmt.from = from
+
+	for {
+		join := joinClause{}
+		switch {
+		case p.acceptKeyword("JOIN"):
+		case p.acceptKeyword("INNER"):
+			if err := p.expectKeyword("JOIN"); err != nil {
+				return nil, err
+			}
+		case p.acceptKeyword("LEFT"):
+			p.acceptKeyword("OUTER")
+			if err := p.expectKeyword("JOIN"); err != nil {
+				return nil, err
+			}
+			join.left = true
+		default:
+			goto joinsDone
+		}
+
+		if join.table, err = p.parseTableRef(); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("ON"); err != nil {
+			return nil, err
+		}
+		if join.on, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		stmt.joins = append(stmt.joins, join)
+	}
+joinsDone:
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("GROUP") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			stmt.groupBy = append(stmt.groupBy, e)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("HAVING") {
+		if stmt.having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item := orderItem{expr: e}
+			if p.acceptKeyword("DESC") {
+				item.desc = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			stmt.orderBy = append(stmt.orderBy, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		if stmt.limit, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		if p.acceptKeyword("OFFSET") {
+			if stmt.offset, err = p.parseInt(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	t := p.peek()
+	if t.kind != tokenNumber {
+		return 0, p.errorf("expected integer")
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil || n < 0 {
+		return 0, p.errorf("expected non-negative integer")
+	}
+	p.pos++
+	return n, nil
+}
+
+func (p *parser) parseSelectItem() (selectItem, error) {
+	if p.acceptSymbol("*") {
+		return selectItem{star: true}, nil
+	}
+
+	// table.*
+	if t := p.peek(); t.kind == tokenIdent || t.kind == tokenQuotedIdent {
+		if p.tokens[p.pos+1].isSymbol(".") && p.tokens[p.pos+2].isSymbol("*") {
+			p.pos += 3
+			return selectItem{star: true, starTable: t.text}, nil
+		}
+	}
+
+	start := p.peek().start
+	e, err := p.parseExpr()
+	if err != nil {
+		return selectItem{}, err
+	}
+	end := p.tokens[p.pos-1].end
+
+	alias, err := p.parseAlias()
+	if err != nil {
+		return selectItem{}, err
+	}
+	return selectItem{expr: e, alias: alias, text: p.query[start:end]}, nil
+}
+
+func (p *parser) parseTableRef() (tableRef, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return tableRef{}, err
+	}
+	alias, err := p.parseAlias()
+	if err != nil {
+		return tableRef{}, err
+	}
+	if alias == "" {
+		alias = name
+	}
+	return tableRef{name: name, alias: alias}, nil
+}
+
+// 表达式按优先级从低到高解析：OR、AND、NOT、比较、加减和||、乘除、一元运算、基本表达式
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.acceptKeyword("NOT") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "NOT", x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenSymbol && (t.text == "=" || t.text == "!=" || t.text == "<>" ||
+			t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+			p.pos++
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			op := t.text
+			if op == "<>" {
+				op = "!="
+			}
+			left = &binaryExpr{op: op, left: left, right: right}
+
+		case t.isKeyword("IS"):
+			p.pos++
+			not := p.acceptKeyword("NOT")
+			if err := p.expectKeyword("NULL"); err != nil {
+				return nil, err
+			}
+			left = &isNullExpr{x: left, not: not}
+
+		case t.isKeyword("NOT") || t.isKeyword("IN") || t.isKeyword("LIKE") || t.isKeyword("BETWEEN"):
+			p.pos++
+			not := false
+			if t.isKeyword("NOT") {
+				not = true
+				t = p.next()
+			}
+			switch {
+			case t.isKeyword("IN"):
+				list, err := p.parseExprList()
+				if err != nil {
+					return nil, err
+				}
+				left = &inExpr{x: left, list: list, not: not}
+			case t.isKeyword("LIKE"):
+				pattern, err := p.parseAdditive()
+				if err != nil {
+					return nil, err
+				}
+				left = &likeExpr{x: left, pattern: pattern, not: not}
+			case t.isKeyword("BETWEEN"):
+				low, err := p.parseAdditive()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectKeyword("AND"); err != nil {
+					return nil, err
+				}
+				high, err := p.parseAdditive()
+				if err != nil {
+					return nil, err
+				}
+				left = &betweenExpr{x: left, low: low, high: high, not: not}
+			default:
+				return nil, p.errorf("expected IN, LIKE or BETWEEN after NOT")
+			}
+
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *parser) parseExprList() ([]expr, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var list []expr
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !t.isSymbol("+") && !t.isSymbol("-") && !t.isSymbol("||") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !t.isSymbol("*") && !t.isSymbol("/") && !t.isSymbol("%") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.acceptSymbol("-") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", x: x}, nil
+	}
+	if p.acceptSymbol("+") {
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenNumber:
+		p.pos++
+		if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+			return nil, fmt.Errorf("sql: invalid number %q at position %d", t.text, t.start)
+		}
+		return &literalExpr{val: numberLiteral(t.text)}, nil
+
+	case t.kind == tokenString:
+		p.pos++
+		return &literalExpr{val: stringValue(t.text)}, nil
+
+	case t.isSymbol("("):
+		p.pos++
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+
+	case t.isKeyword("NULL"):
+		p.pos++
+		return &literalExpr{val: nullValue}, nil
+
+	case t.isKeyword("TRUE"):
+		p.pos++
+		return &literalExpr{val: boolValue(true)}, nil
+
+	case t.isKeyword("FALSE"):
+		p.pos++
+		return &literalExpr{val: boolValue(false)}, nil
+
+	case t.isKeyword("CASE"):
+		p.pos++
+		return p.parseCase()
+
+	case t.kind == tokenIdent && p.tokens[p.pos+1].isSymbol("("):
+		p.pos += 2
+		return p.parseFunc(strings.ToUpper(t.text))
+
+	case t.kind == tokenIdent || t.kind == tokenQuotedIdent:
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		if p.acceptSymbol(".") {
+			column, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			return &columnExpr{table: name, name: column}, nil
+		}
+		return &columnExpr{name: name}, nil
+
+	default:
+		if t.kind == tokenEOF {
+			return nil, p.errorf("unexpected end of query")
+		}
+		return nil, p.errorf("unexpected %q", t.text)
+	}
+}
+
+func (p *parser) parseFunc(name string) (expr, error) {
+	f := &funcExpr{name: name}
+	if p.acceptSymbol("*") {
+		if name != "COUNT" {
+			return nil, p.errorf("%s(*) is not supported", name)
+		}
+		f.star = true
+		return f, p.expectSymbol(")")
+	}
+	if p.acceptSymbol(")") {
+		return f, nil
+	}
+
+	f.distinct = p.acceptKeyword("DISTINCT")
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		f.args = append(f.args, e)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return f, p.expectSymbol(")")
+}
+
+func (p *parser) parseCase() (expr, error) {
+	c := &caseExpr{}
+	if !p.peek().isKeyword("WHEN") {
+		operand, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.operand = operand
+	}
+
+	for p.acceptKeyword("WHEN") {
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+		result, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.whens = append(c.whens, caseWhen{cond: cond, result: result})
+	}
+	if len(c.whens) == 0 {
+		return nil, p.errorf("expected WHEN")
+	}
+
+	if p.acceptKeyword("ELSE") {
+		elseExpr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.elseExpr = elseExpr
+	}
+	return c, p.expectKeyword("END")
+}
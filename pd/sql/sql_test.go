@@ -0,0 +1,209 @@
+package sql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	pd "github.com/wuyyyyyou/go-share/pd/v3"
+)
+
+// newTestDB 读取 testdata/shop.xlsx，users中有两个只有最后一位不同的18位身份证号，以及 01234 和 1234 两个邮编
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	e := pd.NewExcel()
+	if err := e.ReadExcelAllSheet("testdata/shop.xlsx"); err != nil {
+		t.Fatal(err)
+	}
+	db := NewDB()
+	db.RegisterExcel(e)
+	return db
+}
+
+// table 将结果转为表头加所有行
+func table(df *pd.DataFrame) [][]string {
+	return append([][]string{df.GetHeads()}, df.GetRows()...)
+}
+
+func TestQuery(t *testing.T) {
+	db := newTestDB(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  [][]string
+	}{
+		{
+			name:  "select columns with alias",
+			query: `SELECT name, "年龄" AS age FROM users WHERE id = 3`,
+			want:  [][]string{{"name", "age"}, {"carol", "35"}},
+		},
+		{
+			name:  "long id compared as text",
+			query: `SELECT name FROM users WHERE id = '110101199003077777'`,
+			want:  [][]string{{"name"}, {"alice"}},
+		},
+		{
+			name:  "long id number literal matches only the same text",
+			query: `SELECT name FROM users WHERE id = 110101199003077778`,
+			want:  [][]string{{"name"}, {"bob"}},
+		},
+		{
+			name:  "zip keeps leading zero",
+			query: `SELECT name FROM users WHERE zip = '1234'`,
+			want:  [][]string{{"name"}, {"bob"}},
+		},
+		{
+			name:  "numeric comparison on plain numbers",
+			query: `SELECT name FROM users WHERE "年龄" > 26 ORDER BY name`,
+			want:  [][]string{{"name"}, {"alice"}, {"carol"}},
+		},
+		{
+			name:  "null handling",
+			query: `SELECT name FROM users WHERE city IS NULL OR "年龄" IS NULL`,
+			want:  [][]string{{"name"}, {"dave"}},
+		},
+		{
+			name:  "in between like",
+			query: `SELECT name FROM users WHERE "年龄" BETWEEN 25 AND 30 AND city IN ('北京', '上海') AND name LIKE '%o%'`,
+			want:  [][]string{{"name"}, {"bob"}},
+		},
+		{
+			name:  "group by keeps distinct ids and zips",
+			query: `SELECT zip, COUNT(*) AS n FROM users GROUP BY zip ORDER BY zip`,
+			want:  [][]string{{"zip", "n"}, {"01234", "2"}, {"1234", "1"}, {"02134", "1"}},
+		},
+		{
+			name:  "group by having",
+			query: `SELECT user_id, SUM(amount) AS total FROM orders GROUP BY user_id HAVING COUNT(*) > 1`,
+			want:  [][]string{{"user_id", "total"}, {"110101199003077777", "29.5"}},
+		},
+		{
+			name:  "count distinct",
+			query: `SELECT COUNT(DISTINCT user_id) AS users FROM orders`,
+			want:  [][]string{{"users"}, {"4"}},
+		},
+		{
+			name:  "min max in numeric order",
+			query: `SELECT MIN(amount) AS lo, MAX(amount) AS hi FROM orders`,
+			want:  [][]string{{"lo", "hi"}, {"5", "20"}},
+		},
+		{
+			name: "hash join on long ids",
+			query: `SELECT u.name, o.amount FROM users u JOIN orders o ON u.id = o.user_id
+				ORDER BY o.order_id`,
+			want: [][]string{{"name", "amount"}, {"alice", "9.50"}, {"bob", "10"}, {"alice", "20"}, {"carol", "5"}},
+		},
+		{
+			name: "left join keeps unmatched rows",
+			query: `SELECT u.name, COUNT(o.order_id) AS n FROM users u LEFT JOIN orders o ON o.user_id = u.id
+				GROUP BY u.name ORDER BY n DESC, u.name`,
+			want: [][]string{{"name", "n"}, {"alice", "2"}, {"bob", "1"}, {"carol", "1"}, {"dave", "0"}},
+		},
+		{
+			name: "nested loop join",
+			query: `SELECT u.name, o.order_id FROM users u JOIN orders o ON u.id = o.user_id AND o.amount >= 10
+				ORDER BY o.order_id`,
+			want: [][]string{{"name", "order_id"}, {"bob", "2"}, {"alice", "3"}},
+		},
+		{
+			name:  "order by alias",
+			query: `SELECT name, "年龄" * 2 AS double FROM users WHERE "年龄" IS NOT NULL ORDER BY double DESC`,
+			want:  [][]string{{"name", "double"}, {"carol", "70"}, {"alice", "60"}, {"bob", "50"}},
+		},
+		{
+			name:  "order by position",
+			query: `SELECT order_id, amount FROM orders ORDER BY 2`,
+			want:  [][]string{{"order_id", "amount"}, {"4", "5"}, {"5", "7"}, {"1", "9.50"}, {"2", "10"}, {"3", "20"}},
+		},
+		{
+			name:  "order by long ids",
+			query: `SELECT id FROM users ORDER BY id DESC LIMIT 2`,
+			want:  [][]string{{"id"}, {"110101199003077778"}, {"110101199003077777"}},
+		},
+		{
+			name:  "limit offset",
+			query: `SELECT order_id FROM orders ORDER BY order_id LIMIT 2 OFFSET 3`,
+			want:  [][]string{{"order_id"}, {"4"}, {"5"}},
+		},
+		{
+			name:  "distinct",
+			query: `SELECT DISTINCT city FROM users WHERE city IS NOT NULL ORDER BY city`,
+			want:  [][]string{{"city"}, {"上海"}, {"北京"}},
+		},
+		{
+			name:  "computed numbers",
+			query: `SELECT 0.1 + 0.2 AS sum, 7 / 2 AS half, ROUND(2.345, 2) AS r FROM orders LIMIT 1`,
+			want:  [][]string{{"sum", "half", "r"}, {"0.3", "3.5", "2.35"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, err := db.Query(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := table(df); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	db := newTestDB(t)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`SELECT name users`, "FROM"},
+		{`SELECT name FROM missing`, "cannot find table"},
+		{`SELECT missing FROM users`, "missing"},
+		{`SELECT name FROM users WHERE`, "position"},
+		{`SELECT name FROM users LIMIT x`, "expected integer"},
+		{`SELECT name FROM users u JOIN users u ON u.id = u.id`, "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := db.Query(tt.query)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %q does not contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b value
+		want int
+	}{
+		{cellValue("110101199003077777"), cellValue("110101199003077778"), -1},
+		{cellValue("110101199003077777"), numberLiteral("110101199003077777"), 0},
+		{cellValue("110101199003077777"), numberLiteral("110101199003078000"), -1},
+		{cellValue("01234"), cellValue("1234"), -1},
+		{cellValue("01234"), numberLiteral("1234"), -1},
+		{cellValue("9"), cellValue("10"), -1},
+		{cellValue("10"), numberLiteral("10.0"), 0},
+		{cellValue("10.0"), numberLiteral("10"), 1},
+		{numberValue(0.1 + 0.2), numberLiteral("0.3"), 0},
+		{cellValue("abc"), cellValue("abd"), -1},
+	}
+	for _, tt := range tests {
+		if got := compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("compare(%q, %q) = %d, want %d", tt.a.str, tt.b.str, got, tt.want)
+		}
+		if tt.want != 0 && tt.a.key() == tt.b.key() {
+			t.Errorf("%q and %q have the same key", tt.a.str, tt.b.str)
+		}
+		if tt.want == 0 && tt.a.key() != tt.b.key() {
+			t.Errorf("%q and %q have different keys", tt.a.str, tt.b.str)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package sql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// value sql中的值，df中的单元格都是字符串，空单元格视为NULL
+// numeric 为true时是数字常量或计算得到的数字，单元格中的文本只有能原样转换回来时才按数字比较
+type value struct {
+	str     string
+	null    bool
+	numeric bool
+}
+
+var nullValue = value{null: true}
+
+func stringValue(s string) value {
+	return value{str: s}
+}
+
+// cellValue 将单元格转换为值，空字符串为NULL
+func cellValue(s string) value {
+	if s == "" {
+		return nullValue
+	}
+	return value{str: s}
+}
+
+// numberLiteral sql中的数字常量，保留原来的写法
+func numberLiteral(s string) value {
+	return value{str: s, numeric: true}
+}
+
+func numberValue(f float64) value {
+	return value{str: formatNumber(f), numeric: true}
+}
+
+func boolValue(b bool) value {
+	if b {
+		return value{str: "1", numeric: true}
+	}
+	return value{str: "0", numeric: true}
+}
+
+// formatNumber 与pd中的格式相同，不带多余的0，保留15位有效数字以去掉浮点误差
+func formatNumber(f float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+func (v value) number() (float64, bool) {
+	if v.null {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v.str), 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// mustNumber 算术运算中使用，NULL返回false，无法转换为数字时返回错误
+func (v value) mustNumber() (float64, bool, error) {
+	if v.null {
+		return 0, false, nil
+	}
+	f, ok := v.number()
+	if !ok {
+		return 0, false, fmt.Errorf("sql: cannot convert %q to number", v.str)
+	}
+	return f, true, nil
+}
+
+// truth 返回值的真假，NULL返回false，数字非0或字符串为true时为真
+func (v value) truth() bool {
+	if v.null {
+		return false
+	}
+	if f, ok := v.number(); ok {
+		return f != 0
+	}
+	return strings.EqualFold(v.str, "true")
+}
+
+// exactNumber 能否不丢失精度地按数字比较，数字常量和计算结果需要在15位有效数字内，
+// 单元格中的文本还需要与格式化后的数字完全相同，身份证号、超过15位的编号、01234 这样的邮编都不是数字
+func (v value) exactNumber() (float64, bool) {
+	f, ok := v.number()
+	if !ok {
+		return 0, false
+	}
+	canonical := formatNumber(f)
+	if v.numeric {
+		rounded, _ := strconv.ParseFloat(canonical, 64)
+		return f, rounded == f
+	}
+	return f, canonical == v.str
+}
+
+// text 比较和分组时使用的文本，exactNumber 为格式化后的数字，使常量 10.0 与单元格中的 10 相同
+func (v value) text() string {
+	if f, ok := v.exactNumber(); ok {
+		return formatNumber(f)
+	}
+	return v.str
+}
+
+// compare 判断是否相等，两个值都是 exactNumber 时按数值比较，否则按 text 比较，调用前需要排除NULL
+func compare(a, b value) int {
+	aFloat, aOk := a.exactNumber()
+	bFloat, bOk := b.exactNumber()
+	if aOk && bOk {
+		return compareFloat(aFloat, bFloat)
+	}
+	return strings.Compare(a.text(), b.text())
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareForSort 比较大小、排序和 MIN、MAX 时使用，NULL最小，能转换为数字的值排在其他值之前并按数值比较，
+// 数值相同时再按 text 比较，使 9.50 和 10 按数值排序，超过15位的编号仍然能区分先后，结果为0时与 compare 一致
+func compareForSort(a, b value) int {
+	switch {
+	case a.null && b.null:
+		return 0
+	case a.null:
+		return -1
+	case b.null:
+		return 1
+	}
+
+	aFloat, aOk := a.number()
+	bFloat, bOk := b.number()
+	switch {
+	case aOk && bOk:
+		if cmp := compareFloat(aFloat, bFloat); cmp != 0 {
+			return cmp
+		}
+	case aOk:
+		return -1
+	case bOk:
+		return 1
+	}
+	return strings.Compare(a.text(), b.text())
+}
+
+// key 用于分组、去重和哈希连接，与 compare 判断相等的规则一致，不会把丢失精度的浮点数作为键
+func (v value) key() string {
+	if v.null {
+		return "\x00"
+	}
+	return v.text()
+}
@@ -0,0 +1,45 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	pd "github.com/wuyyyyyou/go-share/pd/v3"
+)
+
+// RegisterTable 注册表，同名的表会被覆盖，查询时不会修改df
+func (db *DB) RegisterTable(name string, df *pd.DataFrame) {
+	db.tables[name] = df
+}
+
+// RegisterExcel 将excel中的每个sheet注册为同名的表
+func (db *DB) RegisterExcel(e *pd.Excel) {
+	for sheetName, df := range e.DataFramesMap {
+		db.RegisterTable(sheetName, df)
+	}
+}
+
+// Query 执行SELECT查询并以df返回结果，空单元格视为NULL，结果中的NULL写为空字符串
+// 支持 DISTINCT、表达式和别名、[INNER|LEFT] JOIN ... ON、WHERE、GROUP BY、HAVING、ORDER BY、LIMIT ... OFFSET，
+// 聚合函数支持 COUNT、SUM、AVG、MIN、MAX，包含中文等特殊字符的表名和列名可以用双引号、反引号或方括号包裹
+// 单元格按文本判断相等，与格式化后的数字完全相同的文本才按数值比较，因此身份证号、01234 这样的编号不会被合并
+func (db *DB) Query(query string) (*pd.DataFrame, error) {
+	stmt, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return newExecutor(db).execute(stmt)
+}
+
+// table 按名称查找表，找不到时不区分大小写再查找一次
+func (db *DB) table(name string) (*pd.DataFrame, error) {
+	if df, ok := db.tables[name]; ok {
+		return df, nil
+	}
+	for tableName, df := range db.tables {
+		if strings.EqualFold(tableName, name) {
+			return df, nil
+		}
+	}
+	return nil, fmt.Errorf("sql: cannot find table %s", name)
+}
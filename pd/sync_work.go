@@ -68,12 +68,12 @@ func (df *SyncDataFrame) UniqueRows() {
 	df.DataFrame.UniqueRows()
 }
 
-func (df *SyncDataFrame) ReadExcel(src string) error {
+func (df *SyncDataFrame) ReadExcel(src string, opts ...ReadOption) error {
 	df.rowLock.Lock()
 	df.headLock.Lock()
 	defer df.headLock.Unlock()
 	defer df.rowLock.Unlock()
-	return df.DataFrame.ReadExcel(src)
+	return df.DataFrame.ReadExcel(src, opts...)
 }
 
 func (df *SyncDataFrame) SaveExcel(dst string) error {
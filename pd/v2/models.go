@@ -27,3 +27,25 @@ func NewDataFrame(sheetName string) *DataFrame {
 		headIndexMap: make(map[string]int),
 	}
 }
+
+type readOptions struct {
+	password string
+}
+
+// ReadOption 读取xlsx文件时的可选参数
+type ReadOption func(*readOptions)
+
+// WithReadPassword 设置打开加密文件的密码
+func WithReadPassword(password string) ReadOption {
+	return func(o *readOptions) {
+		o.password = password
+	}
+}
+
+func newReadOptions(opts []ReadOption) *readOptions {
+	o := &readOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
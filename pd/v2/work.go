@@ -312,8 +312,10 @@ func (e *Excel) AppendSheet(dfs ...*DataFrame) {
 	}
 }
 
-func (e *Excel) ReadExcelAllSheet(src string) error {
-	file, err := excelize.OpenFile(src)
+// ReadExcelAllSheet 读取所有sheet，加密的文件通过 WithReadPassword 传入密码
+func (e *Excel) ReadExcelAllSheet(src string, opts ...ReadOption) error {
+	o := newReadOptions(opts)
+	file, err := excelize.OpenFile(src, excelize.Options{Password: o.password})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,118 @@
+package pd
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AggFunc 将一组值聚合为一个值
+type AggFunc func(values []string) string
+
+// formatNumber 数值统一格式化为不带多余0的十进制字符串，保留15位有效数字以去掉浮点误差
+func formatNumber(f float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// parseNumbers 解析能转换为数字的值，空值和非数字会被忽略
+func parseNumbers(values []string) []float64 {
+	var numbers []float64
+	for _, value := range values {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			numbers = append(numbers, f)
+		}
+	}
+	return numbers
+}
+
+// AggSum 求和，非数字的值会被忽略
+func AggSum(values []string) string {
+	sum := 0.0
+	for _, f := range parseNumbers(values) {
+		sum += f
+	}
+	return formatNumber(sum)
+}
+
+// AggMean 求平均值，没有数字时返回空字符串
+func AggMean(values []string) string {
+	numbers := parseNumbers(values)
+	if len(numbers) == 0 {
+		return ""
+	}
+	sum := 0.0
+	for _, f := range numbers {
+		sum += f
+	}
+	return formatNumber(sum / float64(len(numbers)))
+}
+
+// AggMin 求最小值，没有数字时返回空字符串
+func AggMin(values []string) string {
+	numbers := parseNumbers(values)
+	if len(numbers) == 0 {
+		return ""
+	}
+	result := numbers[0]
+	for _, f := range numbers[1:] {
+		if f < result {
+			result = f
+		}
+	}
+	return formatNumber(result)
+}
+
+// AggMax 求最大值，没有数字时返回空字符串
+func AggMax(values []string) string {
+	numbers := parseNumbers(values)
+	if len(numbers) == 0 {
+		return ""
+	}
+	result := numbers[0]
+	for _, f := range numbers[1:] {
+		if f > result {
+			result = f
+		}
+	}
+	return formatNumber(result)
+}
+
+// AggCount 统计非空值的数量
+func AggCount(values []string) string {
+	count := 0
+	for _, value := range values {
+		if value != "" {
+			count++
+		}
+	}
+	return strconv.Itoa(count)
+}
+
+// AggFirst 返回第一个值
+func AggFirst(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// AggLast 返回最后一个值
+func AggLast(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// AggJoin 返回用sep连接所有非空值的聚合函数
+func AggJoin(sep string) AggFunc {
+	return func(values []string) string {
+		var nonEmpty []string
+		for _, value := range values {
+			if value != "" {
+				nonEmpty = append(nonEmpty, value)
+			}
+		}
+		return strings.Join(nonEmpty, sep)
+	}
+}
@@ -0,0 +1,264 @@
+package pd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/wuyyyyyou/go-share/ioutils"
+)
+
+// AppendCsv 将df的所有行追加到csv文件末尾，不会重写已有的内容
+// 文件不存在或为空时先写入表头；文件已有表头时，列按文件中的表头顺序写入，两者的列不一致时返回错误
+// 写入后会调用Sync，适合长时间运行的任务分批写入，中途崩溃时已写入的行不会丢失
+func AppendCsv(dst string, df *DataFrame) error {
+	fileHeads, err := readCsvHeads(dst)
+	if err != nil {
+		return err
+	}
+
+	heads := fileHeads
+	if heads == nil {
+		heads = df.heads
+	}
+	positions, err := appendPositions(heads, df)
+	if err != nil {
+		return fmt.Errorf("append csv %s: %w", dst, err)
+	}
+
+	needNewline, err := missingTrailingNewline(dst)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutils.OpenFileAndAppend(dst)
+	if err != nil {
+		return err
+	}
+	defer ioutils.CloseQuietly(file)
+
+	// 上次写入时中断可能没有换行，避免新行接在最后一行后面
+	if needNewline {
+		if _, err := file.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(file)
+	if fileHeads == nil {
+		if err := writer.Write(heads); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < df.length; i++ {
+		if err := writer.Write(df.pickRecord(i, positions)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// AppendToSheet 将df的所有行追加到xlsx文件中sheet已使用的最后一行之后
+// 文件不存在时会创建，sheet不存在或为空时先写入表头；sheet已有表头时按表头顺序写入，两者的列不一致时返回错误
+// 先写入同一目录下的临时文件再替换dst，中途失败时原文件不会被破坏
+// 加密的文件需要通过 WithReadPassword 传入密码，保存后仍然使用该密码加密
+func AppendToSheet(dst string, sheetName string, df *DataFrame, opts ...ReadOption) error {
+	var file *excelize.File
+	if ioutils.FileExists(dst) {
+		f, err := openExcelFile(dst, opts)
+		if err != nil {
+			return err
+		}
+		file = f
+	} else {
+		file = excelize.NewFile()
+		if err := file.SetSheetName("Sheet1", sheetName); err != nil {
+			return err
+		}
+	}
+	defer ioutils.CloseQuietly(file)
+
+	index, err := file.GetSheetIndex(sheetName)
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		if _, err := file.NewSheet(sheetName); err != nil {
+			return err
+		}
+	}
+
+	fileHeads, usedRows, err := sheetUsedRows(file, sheetName)
+	if err != nil {
+		return err
+	}
+
+	heads := fileHeads
+	if usedRows == 0 {
+		heads = df.heads
+	}
+	positions, err := appendPositions(heads, df)
+	if err != nil {
+		return fmt.Errorf("append sheet %s: %w", sheetName, err)
+	}
+
+	nextRow := usedRows + 1
+	if usedRows == 0 {
+		nextRow++
+	}
+	if lastRow := nextRow + df.length - 1; lastRow > excelize.TotalRows {
+		return fmt.Errorf("sheet %s would have %d rows, exceeds the limit of %d", sheetName, lastRow, excelize.TotalRows)
+	}
+
+	if usedRows == 0 {
+		if err := setSheetRow(file, sheetName, 1, heads); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < df.length; i++ {
+		if err := setSheetRow(file, sheetName, nextRow+i, df.pickRecord(i, positions)); err != nil {
+			return err
+		}
+	}
+
+	return saveFileAtomic(file, dst)
+}
+
+// saveFileAtomic 写入dst所在目录的临时文件，同步到磁盘后重命名为dst
+func saveFileAtomic(file *excelize.File, dst string, opts ...excelize.Options) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		// 重命名成功后临时文件已不存在
+		_ = os.Remove(tmpName)
+	}()
+
+	// 临时文件的权限为0600，改为与原文件相同
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(dst); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		ioutils.CloseQuietly(tmp)
+		return err
+	}
+
+	if err := file.Write(tmp, opts...); err != nil {
+		ioutils.CloseQuietly(tmp)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		ioutils.CloseQuietly(tmp)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, dst)
+}
+
+// appendPositions 返回heads中每一列在df中的位置，heads与df的表头必须包含相同的列，顺序可以不同
+func appendPositions(heads []string, df *DataFrame) ([]int, error) {
+	if missing, extra := lo.Difference(heads, df.heads); len(missing) > 0 || len(extra) > 0 {
+		return nil, fmt.Errorf("heads mismatch, missing %v, unexpected %v", missing, extra)
+	}
+	return df.headPositions(heads)
+}
+
+// pickRecord 按positions的顺序返回一行的值
+func (df *DataFrame) pickRecord(rowIndex int, positions []int) []string {
+	record := make([]string, len(positions))
+	for j, position := range positions {
+		record[j] = df.at(rowIndex, position)
+	}
+	return record
+}
+
+// readCsvHeads 读取csv文件的表头，文件不存在或为空时返回nil
+func readCsvHeads(src string) ([]string, error) {
+	file, err := os.Open(src)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer ioutils.CloseQuietly(file)
+
+	heads, err := csv.NewReader(file).Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	return heads, err
+}
+
+// missingTrailingNewline 文件不为空且最后一个字节不是换行符
+func missingTrailingNewline(src string) (bool, error) {
+	file, err := os.Open(src)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer ioutils.CloseQuietly(file)
+
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+	return last[0] != '\n', nil
+}
+
+// sheetUsedRows 返回sheet的表头和已使用的行数，逐行读取，不会把整个sheet读入内存
+func sheetUsedRows(file *excelize.File, sheetName string) ([]string, int, error) {
+	rows, err := file.Rows(sheetName)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer ioutils.CloseQuietly(rows)
+
+	var heads []string
+	used := 0
+	for n := 1; rows.Next(); n++ {
+		columns, err := rows.Columns()
+		if err != nil {
+			return nil, 0, err
+		}
+		if n == 1 {
+			heads = columns
+		}
+		if len(columns) > 0 {
+			used = n
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return nil, 0, err
+	}
+	return heads, used, nil
+}
+
+func setSheetRow(file *excelize.File, sheetName string, row int, values []string) error {
+	cell, err := excelize.CoordinatesToCellName(1, row)
+	if err != nil {
+		return err
+	}
+	return file.SetSheetRow(sheetName, cell, &values)
+}
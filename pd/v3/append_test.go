@@ -0,0 +1,60 @@
+package pd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAppendToSheet(t *testing.T) {
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "log.xlsx")
+
+	first := NewDataFrame("log")
+	first.SetHeads([]string{"id", "msg"})
+	first.SetRows([][]string{{"1", "start"}})
+	if err := AppendToSheet(dst, "log", first); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dst, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	second := NewDataFrame("log")
+	second.SetHeads([]string{"msg", "id"})
+	second.SetRows([][]string{{"done", "2"}})
+	if err := AppendToSheet(dst, "log", second); err != nil {
+		t.Fatal(err)
+	}
+
+	mismatch := NewDataFrame("log")
+	mismatch.SetHeads([]string{"id"})
+	if err := AppendToSheet(dst, "log", mismatch); err == nil {
+		t.Fatal("expected heads mismatch error")
+	}
+
+	e := NewExcel()
+	if err := e.ReadExcelAllSheet(dst); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"1", "start"}, {"2", "done"}}
+	if got := e.DataFramesMap["log"].GetRows(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows = %q, want %q", got, want)
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Fatalf("mode = %v, want 0640", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temporary files left in %s: %d entries", dir, len(entries))
+	}
+}
@@ -0,0 +1,189 @@
+package pd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type applyOptions struct {
+	ctx         context.Context
+	progress    func(done, total int)
+	errorColumn string
+}
+
+// ApplyOption ParallelApply的可选参数
+type ApplyOption func(*applyOptions)
+
+// WithApplyContext 设置context，取消后不再处理新的行
+func WithApplyContext(ctx context.Context) ApplyOption {
+	return func(o *applyOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithProgress 每处理完一行调用一次，调用是串行的
+func WithProgress(progress func(done, total int)) ApplyOption {
+	return func(o *applyOptions) {
+		o.progress = progress
+	}
+}
+
+// WithErrorColumn 将每行的错误写入指定列而不是返回，成功的行该列为空
+func WithErrorColumn(head string) ApplyOption {
+	return func(o *applyOptions) {
+		o.errorColumn = head
+	}
+}
+
+// RowError 处理某一行时产生的错误
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyError ParallelApply中所有失败的行，按行索引升序排列
+type ApplyError struct {
+	Errors []*RowError
+}
+
+func (e *ApplyError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d rows failed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+type applyResult struct {
+	values map[string]string
+	err    error
+	done   bool
+}
+
+// ParallelApply 使用workers个goroutine对每一行调用fn，fn返回的map按列名写回对应的行，新列按名称排序后追加到heads中
+// fn只能读取传入的行，不能修改df；所有行处理完后才会统一写回，行的顺序保持不变
+// 失败的行默认以 *ApplyError 返回，设置 WithErrorColumn 后写入错误列；context取消时已完成的行仍会写回，并返回context的错误
+func (df *DataFrame) ParallelApply(workers int, fn func(row Row) (map[string]string, error), opts ...ApplyOption) error {
+	o := &applyOptions{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	total := df.length
+	results := make([]applyResult, total)
+	rowIndexes := make(chan int)
+
+	var wg sync.WaitGroup
+	var progressLock sync.Mutex
+	done := 0
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rowIndexes {
+				values, err := fn(df.GetRow(i))
+				results[i] = applyResult{values: values, err: err, done: true}
+
+				if o.progress != nil {
+					progressLock.Lock()
+					done++
+					o.progress(done, total)
+					progressLock.Unlock()
+				}
+			}
+		}()
+	}
+
+	ctxErr := func() error {
+		defer close(rowIndexes)
+		for i := 0; i < total; i++ {
+			// select在两个分支都就绪时随机选择，先检查ctx保证取消后不再分发新行
+			if err := o.ctx.Err(); err != nil {
+				return err
+			}
+			select {
+			case <-o.ctx.Done():
+				return o.ctx.Err()
+			case rowIndexes <- i:
+			}
+		}
+		return nil
+	}()
+	wg.Wait()
+
+	return df.mergeApplyResults(results, o.errorColumn, ctxErr)
+}
+
+// mergeApplyResults 将结果写回df
+func (df *DataFrame) mergeApplyResults(results []applyResult, errorColumn string, ctxErr error) error {
+	newHeads := map[string]bool{}
+	for _, result := range results {
+		for head := range result.values {
+			if _, ok := df.headIndexMap[head]; !ok {
+				newHeads[head] = true
+			}
+		}
+	}
+	if errorColumn != "" {
+		if _, ok := df.headIndexMap[errorColumn]; !ok {
+			newHeads[errorColumn] = true
+		}
+	}
+	if len(newHeads) > 0 {
+		heads := make([]string, 0, len(newHeads))
+		for head := range newHeads {
+			heads = append(heads, head)
+		}
+		sort.Strings(heads)
+		df.heads = append(df.heads, heads...)
+		df.updateHeadIndexMap()
+		df.refreshIndex()
+	}
+
+	var rowErrors []*RowError
+	for i, result := range results {
+		if !result.done {
+			continue
+		}
+
+		for head, value := range result.values {
+			if err := df.SetValueE(i, head, value); err != nil {
+				rowErrors = append(rowErrors, &RowError{Row: i, Err: err})
+			}
+		}
+
+		if errorColumn != "" {
+			message := ""
+			if result.err != nil {
+				message = result.err.Error()
+			}
+			if err := df.SetValueE(i, errorColumn, message); err != nil {
+				rowErrors = append(rowErrors, &RowError{Row: i, Err: err})
+			}
+		} else if result.err != nil {
+			rowErrors = append(rowErrors, &RowError{Row: i, Err: result.err})
+		}
+	}
+
+	if ctxErr != nil {
+		return ctxErr
+	}
+	if len(rowErrors) > 0 {
+		return &ApplyError{Errors: rowErrors}
+	}
+	return nil
+}
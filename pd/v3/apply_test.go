@@ -0,0 +1,31 @@
+package pd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelApplyCancelledContext(t *testing.T) {
+	df := NewDataFrame("apply")
+	df.SetHeads([]string{"id"})
+	for i := 0; i < 1000; i++ {
+		df.AppendRecord([]string{strconv.Itoa(i)})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int32
+	err := df.ParallelApply(4, func(row Row) (map[string]string, error) {
+		atomic.AddInt32(&calls, 1)
+		return map[string]string{"done": "1"}, nil
+	}, WithApplyContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if calls != 0 {
+		t.Fatalf("fn called %d times after the context was cancelled", calls)
+	}
+}
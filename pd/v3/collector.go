@@ -0,0 +1,37 @@
+package pd
+
+func (c *Collector) run() {
+	defer close(c.done)
+
+	batch := make([]map[string]string, 0, c.batchSize)
+	for row := range c.rows {
+		batch = append(batch, row)
+		// 批次已满或暂时没有新的行时写入，避免行长时间停留在批次中
+		if len(batch) >= c.batchSize || len(c.rows) == 0 {
+			c.sdf.appendRows(batch)
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		c.sdf.appendRows(batch)
+	}
+}
+
+// Send 发送一行，可以被多个goroutine同时调用，Close之后不能再调用
+func (c *Collector) Send(row map[string]string) {
+	c.rows <- row
+}
+
+// Rows 返回用于发送行的channel，供需要select的生产者使用
+func (c *Collector) Rows() chan<- map[string]string {
+	return c.rows
+}
+
+// Close 停止接收并等待剩余的行全部写入
+func (c *Collector) Close() {
+	c.closeOnce.Do(func() {
+		close(c.rows)
+	})
+	<-c.done
+}
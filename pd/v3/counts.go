@@ -0,0 +1,178 @@
+package pd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type countOptions struct {
+	margins     bool
+	marginLabel string
+	percent     bool
+	decimals    int
+}
+
+// CountOption ValueCounts和Crosstab的可选参数
+type CountOption func(*countOptions)
+
+// WithMargins 添加合计行（Crosstab还会添加合计列），label为空时使用Total
+func WithMargins(label string) CountOption {
+	return func(o *countOptions) {
+		o.margins = true
+		o.marginLabel = label
+		if o.marginLabel == "" {
+			o.marginLabel = "Total"
+		}
+	}
+}
+
+// WithPercent 比例以百分比格式输出，保留decimals位小数，例如 12.50%
+// Crosstab中使用该选项时，每个单元格输出为占总数的百分比
+func WithPercent(decimals int) CountOption {
+	return func(o *countOptions) {
+		o.percent = true
+		if decimals >= 0 {
+			o.decimals = decimals
+		}
+	}
+}
+
+func newCountOptions(opts []CountOption) *countOptions {
+	o := &countOptions{decimals: 2}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// formatRatio 格式化比例
+func (o *countOptions) formatRatio(count, total int) string {
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(count) / float64(total)
+	}
+	if o.percent {
+		return fmt.Sprintf("%.*f%%", o.decimals, ratio*100)
+	}
+	return formatNumber(ratio)
+}
+
+// countValues 统计列中每个值出现的次数，按次数降序排列，次数相同时按第一次出现的顺序
+func (df *DataFrame) countValues(head string) ([]string, map[string]int, error) {
+	index, ok := df.headIndexMap[head]
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot find head %s", head)
+	}
+
+	var values []string
+	counts := map[string]int{}
+	for i := 0; i < df.length; i++ {
+		value := df.at(i, index)
+		if _, ok := counts[value]; !ok {
+			values = append(values, value)
+		}
+		counts[value]++
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return counts[values[i]] > counts[values[j]]
+	})
+	return values, counts, nil
+}
+
+// ValueCounts 统计列中每个值出现的次数，结果为 [head, count] 两列，按次数降序排列
+// normalize为true时第二列为 proportion，即占总行数的比例，head与第二列重名时第二列加上 _2 后缀
+func (df *DataFrame) ValueCounts(head string, normalize bool, opts ...CountOption) (*DataFrame, error) {
+	o := newCountOptions(opts)
+
+	values, counts, err := df.countValues(head)
+	if err != nil {
+		return nil, err
+	}
+	total := df.length
+
+	format := func(count int) string {
+		if normalize {
+			return o.formatRatio(count, total)
+		}
+		return strconv.Itoa(count)
+	}
+
+	rows := make([][]string, 0, len(values)+1)
+	for _, value := range values {
+		rows = append(rows, []string{value, format(counts[value])})
+	}
+	if o.margins {
+		rows = append(rows, []string{o.marginLabel, format(total)})
+	}
+
+	result := NewDataFrame(df.sheetName)
+	if normalize {
+		result.SetHeads(uniqueHeads([]string{head, "proportion"}))
+	} else {
+		result.SetHeads(uniqueHeads([]string{head, "count"}))
+	}
+	result.SetRows(rows)
+	return result, nil
+}
+
+// Crosstab 交叉频数表，rowHead的每个值为一行，colHead的每个值为一列，单元格为同时出现的次数
+// 行和列都按总次数降序排列，列的值与rowHead或合计列重名时按 uniqueHeads 的规则加上后缀
+func (df *DataFrame) Crosstab(rowHead, colHead string, opts ...CountOption) (*DataFrame, error) {
+	o := newCountOptions(opts)
+
+	rowValues, rowTotals, err := df.countValues(rowHead)
+	if err != nil {
+		return nil, err
+	}
+	colValues, colTotals, err := df.countValues(colHead)
+	if err != nil {
+		return nil, err
+	}
+
+	rowIndex, colIndex := df.headIndexMap[rowHead], df.headIndexMap[colHead]
+	counts := map[string]map[string]int{}
+	for i := 0; i < df.length; i++ {
+		r, c := df.at(i, rowIndex), df.at(i, colIndex)
+		if counts[r] == nil {
+			counts[r] = map[string]int{}
+		}
+		counts[r][c]++
+	}
+
+	total := df.length
+	format := func(count int) string {
+		if o.percent {
+			return o.formatRatio(count, total)
+		}
+		return strconv.Itoa(count)
+	}
+
+	rows := make([][]string, 0, len(rowValues)+1)
+	for _, r := range rowValues {
+		row := []string{r}
+		for _, c := range colValues {
+			row = append(row, format(counts[r][c]))
+		}
+		if o.margins {
+			row = append(row, format(rowTotals[r]))
+		}
+		rows = append(rows, row)
+	}
+
+	heads := append([]string{rowHead}, colValues...)
+	if o.margins {
+		heads = append(heads, o.marginLabel)
+		row := []string{o.marginLabel}
+		for _, c := range colValues {
+			row = append(row, format(colTotals[c]))
+		}
+		rows = append(rows, append(row, format(total)))
+	}
+
+	result := NewDataFrame(df.sheetName)
+	result.SetHeads(uniqueHeads(heads))
+	result.SetRows(rows)
+	return result, nil
+}
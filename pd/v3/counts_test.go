@@ -0,0 +1,37 @@
+package pd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountHeadsDoNotClash(t *testing.T) {
+	df := NewDataFrame("votes")
+	df.SetHeads([]string{"a", "b", "count"})
+	df.SetRows([][]string{{"x", "a", "1"}, {"y", "Total", "1"}, {"x", "a", "1"}})
+
+	crosstab, err := df.Crosstab("a", "b", WithMargins(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "a_2", "Total", "Total_2"}; !reflect.DeepEqual(crosstab.GetHeads(), want) {
+		t.Fatalf("crosstab heads = %q, want %q", crosstab.GetHeads(), want)
+	}
+	if got := crosstab.GetValue(0, "a"); got != "x" {
+		t.Fatalf("row label = %s, want x", got)
+	}
+	if got := crosstab.GetValue(0, "a_2"); got != "2" {
+		t.Fatalf("count = %s, want 2", got)
+	}
+
+	counts, err := df.ValueCounts("count", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"count", "count_2"}; !reflect.DeepEqual(counts.GetHeads(), want) {
+		t.Fatalf("value counts heads = %q, want %q", counts.GetHeads(), want)
+	}
+	if got := counts.GetValue(0, "count_2"); got != "3" {
+		t.Fatalf("count = %s, want 3", got)
+	}
+}
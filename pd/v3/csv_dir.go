@@ -0,0 +1,140 @@
+package pd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/wuyyyyyou/go-share/ioutils"
+)
+
+// CsvManifestName ExportCsvDir 生成的清单文件名，记录sheet顺序和对应的csv文件
+const CsvManifestName = "manifest.json"
+
+type csvManifest struct {
+	Sheets []csvManifestSheet `json:"sheets"`
+}
+
+type csvManifestSheet struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+// ExportCsvDir 每个sheet导出为一个csv文件，并生成记录sheet顺序的清单文件，目录不存在时会自动创建
+func (e *Excel) ExportCsvDir(dir string) error {
+	if err := ioutils.CreateDirsIfNotExists(dir); err != nil {
+		return err
+	}
+
+	manifest := csvManifest{Sheets: []csvManifestSheet{}}
+	used := map[string]bool{strings.ToLower(CsvManifestName): true}
+	for _, sheetName := range e.orderedSheetNames() {
+		fileName := uniqueCsvFileName(sheetName, used)
+		if err := e.DataFramesMap[sheetName].SaveCsv(filepath.Join(dir, fileName)); err != nil {
+			return err
+		}
+		manifest.Sheets = append(manifest.Sheets, csvManifestSheet{Name: sheetName, File: fileName})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, CsvManifestName), data, 0666)
+}
+
+// ImportCsvDir 从目录读取csv文件并追加为sheet，存在清单文件时按清单的顺序和名称读取，
+// 否则读取目录下所有csv文件，按文件名排序，文件名（不含扩展名）作为sheet名称
+func (e *Excel) ImportCsvDir(dir string) error {
+	manifestPath := filepath.Join(dir, CsvManifestName)
+	if !ioutils.FileExists(manifestPath) {
+		return e.ImportCsvFiles(filepath.Join(dir, "*.csv"))
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return err
+	}
+	var manifest csvManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest %s: %w", manifestPath, err)
+	}
+
+	for _, sheet := range manifest.Sheets {
+		df, err := readCsvSheet(filepath.Join(dir, sheet.File), sheet.Name)
+		if err != nil {
+			return err
+		}
+		e.AppendSheet(df)
+	}
+	return nil
+}
+
+// ImportCsvFiles 读取匹配glob的csv文件并追加为sheet，每个glob的结果按文件名排序，
+// 文件名（不含扩展名）作为sheet名称，同一个文件只读取一次
+func (e *Excel) ImportCsvFiles(patterns ...string) error {
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+
+	for _, path := range lo.Uniq(paths) {
+		sheetName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		df, err := readCsvSheet(path, sheetName)
+		if err != nil {
+			return err
+		}
+		e.AppendSheet(df)
+	}
+	return nil
+}
+
+// readCsvSheet 读取csv为sheet，空文件对应空的sheet
+func readCsvSheet(path string, sheetName string) (*DataFrame, error) {
+	df := NewDataFrame(sheetName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return df, nil
+	}
+
+	if err := df.ReadCsv(path); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return df, nil
+}
+
+// uniqueCsvFileName 将sheet名称转换为合法且不重复的文件名，文件系统可能不区分大小写，按小写去重
+func uniqueCsvFileName(sheetName string, used map[string]bool) string {
+	base := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, sheetName)
+	base = strings.Trim(base, " .")
+	if base == "" {
+		base = "Sheet"
+	}
+
+	fileName := base + ".csv"
+	for i := 2; used[strings.ToLower(fileName)]; i++ {
+		fileName = base + "_" + strconv.Itoa(i) + ".csv"
+	}
+	used[strings.ToLower(fileName)] = true
+	return fileName
+}
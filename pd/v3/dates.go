@@ -0,0 +1,195 @@
+package pd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = "2006-01-02 15:04:05"
+	// ExcelSerialLayout 作为layout传入时，数字按excel序列号解析，例如 45321 或带时间的 45321.5
+	// 默认不识别序列号，避免 2024、12 这样的年份或数量被当作日期
+	ExcelSerialLayout = "excel-serial"
+
+	// maxExcelSerial 9999-12-31对应的excel序列号，8位的纯数字按yyyymmdd解析
+	maxExcelSerial = 2958465
+)
+
+// dateLayouts 自动识别时尝试的格式，go解析时单个数字的月、日也能匹配两位数
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-1-2 15:04:05",
+	"2006/1/2 15:04:05",
+	"2006-1-2 15:04",
+	"2006/1/2 15:04",
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"2006年1月2日 15:04:05",
+	"2006年1月2日 15:04",
+	"2006年1月2日",
+	"2006年1月",
+	"20060102",
+	"1-2-06",
+	"1/2/2006",
+	"1/2/06",
+}
+
+// DatePart 从日期中提取的部分
+type DatePart int
+
+const (
+	DateYear DatePart = iota
+	DateQuarter
+	DateMonth
+	// DateWeek ISO周数
+	DateWeek
+	DateDay
+	// DateWeekday 星期几，星期一为1，星期日为7
+	DateWeekday
+	// DateYearMonth 格式为 2024-01
+	DateYearMonth
+	// DateYearWeek ISO年和周，格式为 2024-W01
+	DateYearWeek
+)
+
+// ParseDate 解析日期，先尝试layouts，再自动识别常见格式，没有时区的值按loc解析
+// layouts中包含 ExcelSerialLayout 时才会把数字按excel序列号解析
+func ParseDate(value string, loc *time.Location, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if loc == nil {
+		loc = time.Local
+	}
+
+	// 复制后再追加，不会修改调用方传入的切片
+	candidates := make([]string, 0, len(layouts)+len(dateLayouts))
+	candidates = append(candidates, layouts...)
+	candidates = append(candidates, dateLayouts...)
+	serial := false
+	for _, layout := range candidates {
+		if layout == ExcelSerialLayout {
+			serial = true
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+
+	if serial {
+		if t, ok := parseExcelSerial(value, loc); ok {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse date %q", value)
+}
+
+// parseExcelSerial 解析excel中未设置日期格式的单元格读取到的序列号
+func parseExcelSerial(value string, loc *time.Location) (time.Time, bool) {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial <= 0 || serial > maxExcelSerial {
+		return time.Time{}, false
+	}
+	t, err := excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), true
+}
+
+// ParseDates 解析列中的日期并统一格式，时间为0点的值格式化为 DateLayout，否则为 DateTimeLayout
+// 空值会被跳过，无法解析的值保持不变，并以 *ApplyError 返回
+func (df *DataFrame) ParseDates(head string, layouts ...string) error {
+	return df.formatDates(head, head, time.Local, func(t time.Time) string {
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+			return t.Format(DateLayout)
+		}
+		return t.Format(DateTimeLayout)
+	}, layouts)
+}
+
+// NormalizeDates 解析列中的日期，转换到loc时区后按outLayout格式化，loc为nil时使用本地时区
+func (df *DataFrame) NormalizeDates(head string, outLayout string, loc *time.Location, layouts ...string) error {
+	return df.formatDates(head, head, loc, func(t time.Time) string {
+		return t.Format(outLayout)
+	}, layouts)
+}
+
+// ExtractDatePart 解析列中的日期，将指定部分写入outHead列，用于按年、月、周分组
+func (df *DataFrame) ExtractDatePart(head, outHead string, part DatePart, layouts ...string) error {
+	return df.formatDates(head, outHead, time.Local, func(t time.Time) string {
+		switch part {
+		case DateYear:
+			return strconv.Itoa(t.Year())
+		case DateQuarter:
+			return strconv.Itoa((int(t.Month())-1)/3 + 1)
+		case DateMonth:
+			return strconv.Itoa(int(t.Month()))
+		case DateWeek:
+			_, week := t.ISOWeek()
+			return strconv.Itoa(week)
+		case DateDay:
+			return strconv.Itoa(t.Day())
+		case DateWeekday:
+			weekday := int(t.Weekday())
+			if weekday == 0 {
+				weekday = 7
+			}
+			return strconv.Itoa(weekday)
+		case DateYearMonth:
+			return t.Format("2006-01")
+		case DateYearWeek:
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		default:
+			return ""
+		}
+	}, layouts)
+}
+
+func (df *DataFrame) formatDates(head, outHead string, loc *time.Location, format func(t time.Time) string, layouts []string) error {
+	position, ok := df.headIndexMap[head]
+	if !ok {
+		return fmt.Errorf("cannot find head %s", head)
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var rowErrors []*RowError
+	for i := 0; i < df.length; i++ {
+		value := df.at(i, position)
+		if strings.TrimSpace(value) == "" {
+			if outHead != head {
+				if err := df.SetValueE(i, outHead, ""); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		t, err := ParseDate(value, loc, layouts...)
+		if err != nil {
+			rowErrors = append(rowErrors, &RowError{Row: i, Err: err})
+			if outHead != head {
+				if err := df.SetValueE(i, outHead, ""); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := df.SetValueE(i, outHead, format(t.In(loc))); err != nil {
+			return err
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return &ApplyError{Errors: rowErrors}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package pd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		layouts []string
+		want    string
+	}{
+		{"2024-01-30", nil, "2024-01-30 00:00:00"},
+		{"2024/1/30 8:05", nil, "2024-01-30 08:05:00"},
+		{"2024年1月30日", nil, "2024-01-30 00:00:00"},
+		{"20240130", nil, "2024-01-30 00:00:00"},
+		{"30.01.2024", []string{"02.01.2006"}, "2024-01-30 00:00:00"},
+		{"45321", []string{ExcelSerialLayout}, "2024-01-30 00:00:00"},
+		{"45321.5", []string{ExcelSerialLayout}, "2024-01-30 12:00:00"},
+		// 没有传入 ExcelSerialLayout 时数字不是日期
+		{"45321", nil, ""},
+		{"2024", nil, ""},
+		{"12", nil, ""},
+		{"3.5", nil, ""},
+		{"0", []string{ExcelSerialLayout}, ""},
+		{"abc", []string{ExcelSerialLayout}, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.value, time.UTC, tt.layouts...)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseDate(%q, %q) = %s, want error", tt.value, tt.layouts, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDate(%q, %q) returned %v", tt.value, tt.layouts, err)
+			continue
+		}
+		if s := got.Format(DateTimeLayout); s != tt.want {
+			t.Errorf("ParseDate(%q, %q) = %s, want %s", tt.value, tt.layouts, s, tt.want)
+		}
+	}
+}
+
+func TestParseDateKeepsLayouts(t *testing.T) {
+	backing := []string{"02.01.2006", "unused"}
+	layouts := backing[:1]
+	if _, err := ParseDate("not a date", time.UTC, layouts...); err == nil {
+		t.Fatal("expected error")
+	}
+	if backing[1] != "unused" {
+		t.Fatalf("ParseDate wrote %q into the caller's slice", backing[1])
+	}
+}
+
+func TestParseDatesReportsNumbers(t *testing.T) {
+	df := NewDataFrame("dates")
+	df.SetHeads([]string{"date"})
+	df.SetRows([][]string{{"2024/1/30"}, {"2024"}, {""}, {"12"}})
+
+	err := df.ParseDates("date")
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("err = %v, want *ApplyError", err)
+	}
+	rows := make([]int, len(applyErr.Errors))
+	for i, rowErr := range applyErr.Errors {
+		rows[i] = rowErr.Row
+	}
+	if len(rows) != 2 || rows[0] != 1 || rows[1] != 3 {
+		t.Fatalf("error rows = %v, want [1 3]", rows)
+	}
+	if got := df.GetValue(0, "date"); got != "2024-01-30" {
+		t.Fatalf("date = %s", got)
+	}
+	if got := df.GetValue(1, "date"); got != "2024" {
+		t.Fatalf("unparsed value changed to %s", got)
+	}
+}
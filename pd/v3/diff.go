@@ -0,0 +1,191 @@
+package pd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/wuyyyyyou/go-share/ioutils"
+)
+
+// DiffResult 两个df按键列比较的结果
+type DiffResult struct {
+	Keys []string
+	// AddedHeads 只存在于新df中的列，RemovedHeads 只存在于旧df中的列，这些列不参与比较
+	AddedHeads   []string
+	RemovedHeads []string
+	// Added 新增的行，表头与新df相同；Removed 删除的行，表头与旧df相同
+	Added   *DataFrame
+	Removed *DataFrame
+	Changed []ChangedRow
+
+	newDf *DataFrame
+}
+
+// ChangedRow 键相同但内容不同的行
+type ChangedRow struct {
+	Key      []string
+	OldIndex int
+	NewIndex int
+	Cells    []CellChange
+}
+
+// CellChange 单元格修改前后的值
+type CellChange struct {
+	Head   string
+	Before string
+	After  string
+}
+
+// Diff 按键列比较两个df，返回新增、删除和修改的行，只比较两个df共有的列，键重复时返回错误
+func Diff(oldDf, newDf *DataFrame, keys []string) (*DiffResult, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keys must not be empty")
+	}
+
+	oldIndex, err := oldDf.keyIndex(keys)
+	if err != nil {
+		return nil, fmt.Errorf("old dataframe: %w", err)
+	}
+	newIndex, err := newDf.keyIndex(keys)
+	if err != nil {
+		return nil, fmt.Errorf("new dataframe: %w", err)
+	}
+
+	result := &DiffResult{
+		Keys:         keys,
+		AddedHeads:   lo.Without(newDf.heads, oldDf.heads...),
+		RemovedHeads: lo.Without(oldDf.heads, newDf.heads...),
+		Added:        NewDataFrame(newDf.sheetName),
+		Removed:      NewDataFrame(oldDf.sheetName),
+		newDf:        newDf,
+	}
+	result.Added.SetHeads(append([]string{}, newDf.heads...))
+	result.Removed.SetHeads(append([]string{}, oldDf.heads...))
+
+	commonHeads := lo.Filter(newDf.heads, func(head string, _ int) bool {
+		_, ok := oldDf.headIndexMap[head]
+		return ok && !lo.Contains(keys, head)
+	})
+
+	for i := 0; i < newDf.length; i++ {
+		key := newDf.rowKey(i, keys)
+		j, ok := oldIndex[strings.Join(key, "\x1F")]
+		if !ok {
+			result.Added.AppendRecord(newDf.record(i))
+			continue
+		}
+
+		var cells []CellChange
+		for _, head := range commonHeads {
+			before := oldDf.GetValue(j, head)
+			after := newDf.GetValue(i, head)
+			if before != after {
+				cells = append(cells, CellChange{Head: head, Before: before, After: after})
+			}
+		}
+		if len(cells) > 0 {
+			result.Changed = append(result.Changed, ChangedRow{Key: key, OldIndex: j, NewIndex: i, Cells: cells})
+		}
+	}
+
+	for i := 0; i < oldDf.length; i++ {
+		if _, ok := newIndex[strings.Join(oldDf.rowKey(i, keys), "\x1F")]; !ok {
+			result.Removed.AppendRecord(oldDf.record(i))
+		}
+	}
+
+	return result, nil
+}
+
+// rowKey 返回行在键列上的值
+func (df *DataFrame) rowKey(rowIndex int, keys []string) []string {
+	key := make([]string, len(keys))
+	for i, head := range keys {
+		key[i] = df.GetValue(rowIndex, head)
+	}
+	return key
+}
+
+// keyIndex 返回键到行索引的映射，键列不存在或键重复时返回错误
+func (df *DataFrame) keyIndex(keys []string) (map[string]int, error) {
+	for _, head := range keys {
+		if _, ok := df.headIndexMap[head]; !ok {
+			return nil, fmt.Errorf("cannot find head %s", head)
+		}
+	}
+
+	index := make(map[string]int, df.length)
+	for i := 0; i < df.length; i++ {
+		key := df.rowKey(i, keys)
+		joined := strings.Join(key, "\x1F")
+		if j, ok := index[joined]; ok {
+			return nil, fmt.Errorf("duplicate key %v in rows %d and %d", key, j, i)
+		}
+		index[joined] = i
+	}
+	return index, nil
+}
+
+// IsEmpty 两个df没有任何差异
+func (d *DiffResult) IsEmpty() bool {
+	return d.Added.GetLength() == 0 && d.Removed.GetLength() == 0 && len(d.Changed) == 0 &&
+		len(d.AddedHeads) == 0 && len(d.RemovedHeads) == 0
+}
+
+// SaveExcel 保存差异报告，包含Added、Removed、Changed三个sheet，
+// Changed中为修改后的行，修改过的单元格会高亮显示，并以批注记录修改前的值
+func (d *DiffResult) SaveExcel(dst string) error {
+	file := excelize.NewFile()
+	defer ioutils.CloseQuietly(file)
+
+	if err := file.SetSheetName("Sheet1", "Added"); err != nil {
+		return err
+	}
+	if err := writeSheet(file, "Added", d.Added.GetHeads(), d.Added.GetRows()); err != nil {
+		return err
+	}
+
+	if _, err := file.NewSheet("Removed"); err != nil {
+		return err
+	}
+	if err := writeSheet(file, "Removed", d.Removed.GetHeads(), d.Removed.GetRows()); err != nil {
+		return err
+	}
+
+	if _, err := file.NewSheet("Changed"); err != nil {
+		return err
+	}
+	rows := make([][]string, len(d.Changed))
+	for i, changed := range d.Changed {
+		rows[i] = d.newDf.record(changed.NewIndex)
+	}
+	if err := writeSheet(file, "Changed", d.newDf.heads, rows); err != nil {
+		return err
+	}
+
+	style, err := file.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFEB9C"}},
+	})
+	if err != nil {
+		return err
+	}
+	for i, changed := range d.Changed {
+		for _, cellChange := range changed.Cells {
+			cell, _ := excelize.CoordinatesToCellName(d.newDf.headIndexMap[cellChange.Head]+1, i+2)
+			if err := file.SetCellStyle("Changed", cell, cell, style); err != nil {
+				return err
+			}
+			if err := file.AddComment("Changed", excelize.Comment{
+				Cell: cell,
+				Text: "Before: " + cellChange.Before,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return file.SaveAs(dst)
+}
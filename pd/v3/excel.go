@@ -0,0 +1,145 @@
+package pd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/wuyyyyyou/go-share/ioutils"
+)
+
+// orderedSheetNames 返回 SheetNames 中存在于 DataFramesMap 的sheet，之后是只存在于 DataFramesMap 中的sheet，按名称排序
+func (e *Excel) orderedSheetNames() []string {
+	names := lo.Filter(e.SheetNames, func(name string, _ int) bool {
+		_, ok := e.DataFramesMap[name]
+		return ok
+	})
+
+	var extra []string
+	for name := range e.DataFramesMap {
+		if !lo.Contains(names, name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+
+	return append(lo.Uniq(names), extra...)
+}
+
+// GetSheet 按名称获取sheet
+func (e *Excel) GetSheet(sheetName string) (*DataFrame, bool) {
+	df, ok := e.DataFramesMap[sheetName]
+	return df, ok
+}
+
+// RenameSheet 重命名sheet，保持其在 SheetNames 中的位置
+func (e *Excel) RenameSheet(oldName, newName string) error {
+	df, ok := e.DataFramesMap[oldName]
+	if !ok {
+		return fmt.Errorf("cannot find sheet %s", oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	if _, ok := e.DataFramesMap[newName]; ok {
+		return fmt.Errorf("sheet %s already exists", newName)
+	}
+
+	delete(e.DataFramesMap, oldName)
+	df.SetSheetName(newName)
+	e.DataFramesMap[newName] = df
+	for i, name := range e.SheetNames {
+		if name == oldName {
+			e.SheetNames[i] = newName
+		}
+	}
+	if e.activeSheet == oldName {
+		e.activeSheet = newName
+	}
+	return nil
+}
+
+// RemoveSheet 删除sheet，删除的是活动sheet时会重置为第一个sheet
+func (e *Excel) RemoveSheet(sheetName string) error {
+	if _, ok := e.DataFramesMap[sheetName]; !ok {
+		return fmt.Errorf("cannot find sheet %s", sheetName)
+	}
+
+	delete(e.DataFramesMap, sheetName)
+	e.SheetNames = lo.Without(e.SheetNames, sheetName)
+	if e.activeSheet == sheetName {
+		e.activeSheet = ""
+	}
+	return nil
+}
+
+// MoveSheet 将sheet移动到 SheetNames 中的index位置
+func (e *Excel) MoveSheet(sheetName string, index int) error {
+	if _, ok := e.DataFramesMap[sheetName]; !ok {
+		return fmt.Errorf("cannot find sheet %s", sheetName)
+	}
+
+	names := lo.Without(e.SheetNames, sheetName)
+	if index < 0 || index > len(names) {
+		return fmt.Errorf("sheet index %d out of range", index)
+	}
+
+	e.SheetNames = append(names[:index], append([]string{sheetName}, names[index:]...)...)
+	return nil
+}
+
+// SetActiveSheet 设置保存后打开文件时显示的sheet
+func (e *Excel) SetActiveSheet(sheetName string) error {
+	if _, ok := e.DataFramesMap[sheetName]; !ok {
+		return fmt.Errorf("cannot find sheet %s", sheetName)
+	}
+	e.activeSheet = sheetName
+	return nil
+}
+
+// GetActiveSheet 返回活动sheet的名称，未设置时返回空字符串
+func (e *Excel) GetActiveSheet() string {
+	return e.activeSheet
+}
+
+// ReadSheets 只读取指定的sheet，适用于只需要部分sheet的大文件，sheet按参数的顺序排列
+// 与 ReadExcelAllSheet 相同，之前读取或添加的sheet都会被清除
+func (e *Excel) ReadSheets(src string, sheetNames ...string) error {
+	return e.ReadSheetsWithOptions(src, sheetNames)
+}
+
+// ReadSheetsWithOptions 与 ReadSheets 相同，加密的文件通过 WithReadPassword 传入密码
+func (e *Excel) ReadSheetsWithOptions(src string, sheetNames []string, opts ...ReadOption) error {
+	file, err := openExcelFile(src, opts)
+	if err != nil {
+		return err
+	}
+	defer ioutils.CloseQuietly(file)
+
+	sheetList := file.GetSheetList()
+	for _, sheetName := range sheetNames {
+		if !lo.Contains(sheetList, sheetName) {
+			return fmt.Errorf("cannot find sheet %s", sheetName)
+		}
+	}
+
+	sheetNames = lo.Uniq(sheetNames)
+	dataFramesMap := make(map[string]*DataFrame, len(sheetNames))
+	for _, sheetName := range sheetNames {
+		df, err := readSheet(file, sheetName)
+		if err != nil {
+			return err
+		}
+		dataFramesMap[sheetName] = df
+	}
+
+	e.SheetNames = sheetNames
+	e.DataFramesMap = dataFramesMap
+	e.activeSheet = ""
+	if activeSheet := file.GetSheetName(file.GetActiveSheetIndex()); lo.Contains(sheetNames, activeSheet) {
+		e.activeSheet = activeSheet
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package pd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// rowIndex 键列到行索引的映射，键列的值全部为空的行不会被索引
+type rowIndex struct {
+	cols      []string
+	positions []int
+	unique    bool
+	entries   map[string][]int
+}
+
+// SetIndex 按列建立索引，之后可以通过 Lookup、LookupAll 按键查找行，允许键重复
+func (df *DataFrame) SetIndex(cols ...string) error {
+	return df.setIndex(cols, false)
+}
+
+// SetUniqueIndex 按列建立唯一索引，已有的键重复时返回错误
+// 之后通过 SetValueE、AppendRecordE、AppendRowE、SetRowsE 修改出重复的键会返回错误且不做修改，
+// AppendRecord、AppendRow、SetRows 不返回错误，不会检查唯一性
+func (df *DataFrame) SetUniqueIndex(cols ...string) error {
+	return df.setIndex(cols, true)
+}
+
+func (df *DataFrame) setIndex(cols []string, unique bool) error {
+	if len(cols) == 0 {
+		return fmt.Errorf("index columns must not be empty")
+	}
+
+	index := &rowIndex{cols: append([]string{}, cols...), unique: unique}
+	if err := index.rebuild(df); err != nil {
+		return err
+	}
+	df.index = index
+	return nil
+}
+
+// DropIndex 删除索引
+func (df *DataFrame) DropIndex() {
+	df.index = nil
+}
+
+// RebuildIndex 重建索引并检查唯一索引是否仍然成立
+// SetRows、AppendRow等批量修改会同步更新索引，但不会检查唯一性，可以在修改后调用
+func (df *DataFrame) RebuildIndex() error {
+	if df.index == nil {
+		return fmt.Errorf("index is not set")
+	}
+	return df.index.rebuild(df)
+}
+
+// Lookup 按键查找第一行，键的数量必须与索引列的数量一致
+// 批量修改后唯一索引中出现重复的键时，返回索引最小的行
+func (df *DataFrame) Lookup(key ...string) (Row, bool) {
+	rowIndexes := df.lookupRowIndexes(key)
+	if len(rowIndexes) == 0 {
+		return Row{}, false
+	}
+	return df.GetRow(rowIndexes[0]), true
+}
+
+// LookupAll 按键查找所有行，按行索引升序返回
+func (df *DataFrame) LookupAll(key ...string) []Row {
+	return lo.Map(df.lookupRowIndexes(key), func(rowIndex int, _ int) Row {
+		return df.GetRow(rowIndex)
+	})
+}
+
+func (df *DataFrame) lookupRowIndexes(key []string) []int {
+	if df.index == nil || len(key) != len(df.index.cols) {
+		return nil
+	}
+	return df.index.entries[joinIndexKey(key)]
+}
+
+// refreshIndex 批量修改后立即重建索引，查找时不会再修改索引，多个读者可以并发查找
+// 唯一性的检查交给 RebuildIndex，索引列不存在时索引为空
+func (df *DataFrame) refreshIndex() {
+	if df.index != nil {
+		_ = df.index.rebuild(df)
+	}
+}
+
+// indexLastRow 将追加的最后一行加入索引
+func (df *DataFrame) indexLastRow() {
+	index := df.index
+	if index == nil || index.positions == nil {
+		return
+	}
+	rowIndex := df.length - 1
+	if key, ok := index.rowKey(df, rowIndex, -1, ""); ok {
+		index.entries[key] = append(index.entries[key], rowIndex)
+	}
+}
+
+func (index *rowIndex) rebuild(df *DataFrame) error {
+	index.entries = map[string][]int{}
+	index.positions = nil
+
+	positions := make([]int, len(index.cols))
+	for i, col := range index.cols {
+		position, ok := df.headIndexMap[col]
+		if !ok {
+			return fmt.Errorf("cannot find head %s", col)
+		}
+		positions[i] = position
+	}
+	index.positions = positions
+
+	var err error
+	for i := 0; i < df.length; i++ {
+		key, ok := index.rowKey(df, i, -1, "")
+		if !ok {
+			continue
+		}
+		if index.unique && len(index.entries[key]) > 0 && err == nil {
+			err = fmt.Errorf("duplicate index key %v in rows %d and %d",
+				strings.Split(key, "\x1F"), index.entries[key][0], i)
+		}
+		index.entries[key] = append(index.entries[key], i)
+	}
+	return err
+}
+
+// checkUniqueKey 键列的值为values时，检查唯一索引中是否已有相同的键
+func (df *DataFrame) checkUniqueKey(values []string) error {
+	index := df.index
+	if index == nil || !index.unique || index.positions == nil {
+		return nil
+	}
+	if lo.EveryBy(values, func(value string) bool { return value == "" }) {
+		return nil
+	}
+	key := joinIndexKey(values)
+	if rowIndexes := index.entries[key]; len(rowIndexes) > 0 {
+		return fmt.Errorf("duplicate index key %v in rows %d and %d", values, rowIndexes[0], df.length)
+	}
+	return nil
+}
+
+// checkUniqueRows 检查rows替换所有行后唯一索引是否仍然成立
+func (df *DataFrame) checkUniqueRows(rows [][]string) error {
+	index := df.index
+	if index == nil || !index.unique || index.positions == nil {
+		return nil
+	}
+	seen := map[string]int{}
+	for i, row := range rows {
+		values := lo.Map(index.positions, func(position int, _ int) string {
+			if position < len(row) {
+				return row[position]
+			}
+			return ""
+		})
+		if lo.EveryBy(values, func(value string) bool { return value == "" }) {
+			continue
+		}
+		key := joinIndexKey(values)
+		if first, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate index key %v in rows %d and %d", values, first, i)
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// checkUniqueColumns 检查把values写入positions对应的列后唯一索引是否仍然成立，values[j]为第j列所有行的值
+func (df *DataFrame) checkUniqueColumns(positions []int, values [][]string) error {
+	index := df.index
+	if index == nil || !index.unique || index.positions == nil {
+		return nil
+	}
+	replaced := map[int][]string{}
+	for j, position := range positions {
+		replaced[position] = values[j]
+	}
+	if !lo.SomeBy(index.positions, func(position int) bool {
+		_, ok := replaced[position]
+		return ok
+	}) {
+		return nil
+	}
+
+	seen := map[string]int{}
+	for i := 0; i < df.length; i++ {
+		keyValues := lo.Map(index.positions, func(position int, _ int) string {
+			if column, ok := replaced[position]; ok {
+				return column[i]
+			}
+			return df.at(i, position)
+		})
+		if lo.EveryBy(keyValues, func(value string) bool { return value == "" }) {
+			continue
+		}
+		key := joinIndexKey(keyValues)
+		if first, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate index key %v in rows %d and %d", keyValues, first, i)
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// copyIndex 按df的索引列为newDf建立相同的索引
+func (df *DataFrame) copyIndex(newDf *DataFrame) {
+	if df.index == nil {
+		return
+	}
+	newDf.index = &rowIndex{cols: append([]string{}, df.index.cols...), unique: df.index.unique}
+	newDf.refreshIndex()
+}
+
+// rowKey 计算行的键，position列的值替换为value，position为-1时不替换，键列全部为空时返回false
+func (index *rowIndex) rowKey(df *DataFrame, rowIndex int, position int, value string) (string, bool) {
+	values := make([]string, len(index.positions))
+	empty := true
+	for i, p := range index.positions {
+		switch {
+		case p == position:
+			values[i] = value
+		default:
+			values[i] = df.at(rowIndex, p)
+		}
+		if values[i] != "" {
+			empty = false
+		}
+	}
+	return joinIndexKey(values), !empty
+}
+
+// setIndexedValue 设置值并同步更新索引
+func (df *DataFrame) setIndexedValue(rowIndex int, position int, value string) error {
+	index := df.index
+	if !lo.Contains(index.positions, position) {
+		df.setValue(rowIndex, position, value)
+		return nil
+	}
+
+	oldKey, oldOk := index.rowKey(df, rowIndex, -1, "")
+	newKey, newOk := index.rowKey(df, rowIndex, position, value)
+
+	if newOk && newKey != oldKey && index.unique && len(index.entries[newKey]) > 0 {
+		return fmt.Errorf("duplicate index key %v in rows %d and %d",
+			strings.Split(newKey, "\x1F"), index.entries[newKey][0], rowIndex)
+	}
+
+	df.setValue(rowIndex, position, value)
+	if oldOk == newOk && oldKey == newKey {
+		return nil
+	}
+
+	if oldOk {
+		index.entries[oldKey] = lo.Without(index.entries[oldKey], rowIndex)
+		if len(index.entries[oldKey]) == 0 {
+			delete(index.entries, oldKey)
+		}
+	}
+	if newOk {
+		rowIndexes := index.entries[newKey]
+		i := sort.SearchInts(rowIndexes, rowIndex)
+		rowIndexes = append(rowIndexes, 0)
+		copy(rowIndexes[i+1:], rowIndexes[i:])
+		rowIndexes[i] = rowIndex
+		index.entries[newKey] = rowIndexes
+	}
+	return nil
+}
+
+func joinIndexKey(key []string) string {
+	return strings.Join(key, "\x1F")
+}
@@ -0,0 +1,147 @@
+package pd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newIndexTestDataFrame(t *testing.T) *DataFrame {
+	t.Helper()
+	df := NewDataFrame("users")
+	df.SetHeads([]string{"id", "name"})
+	df.SetRows([][]string{{"1", "alice"}, {"2", "bob"}})
+	if err := df.SetUniqueIndex("id"); err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+func TestUniqueIndexAfterAppend(t *testing.T) {
+	df := newIndexTestDataFrame(t)
+	df.AppendRecord([]string{"3", "carol"})
+
+	if row, ok := df.Lookup("3"); !ok || row.Get("name") != "carol" {
+		t.Fatalf("Lookup(3) = %v, %v", row.Values(), ok)
+	}
+	if err := df.SetValueE(2, "id", "1"); err == nil {
+		t.Fatal("SetValueE with duplicate key returned nil")
+	}
+	if rows := df.LookupAll("1"); len(rows) != 1 {
+		t.Fatalf("LookupAll(1) returned %d rows", len(rows))
+	}
+	if got := df.GetValue(2, "id"); got != "3" {
+		t.Fatalf("rejected SetValueE changed value to %s", got)
+	}
+}
+
+func TestIndexFollowsBulkChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(df *DataFrame)
+		key    string
+		want   int
+	}{
+		{"SetRows", func(df *DataFrame) { df.SetRows([][]string{{"9", "zed"}}) }, "9", 1},
+		{"SetRows removes old keys", func(df *DataFrame) { df.SetRows([][]string{{"9", "zed"}}) }, "1", 0},
+		{"AppendRow", func(df *DataFrame) { df.AppendRow(map[string]string{"id": "4", "age": "30"}) }, "4", 1},
+		{"UniqueRows", func(df *DataFrame) { df.AppendRecord([]string{"1", "alice"}); df.UniqueRows() }, "1", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df := newIndexTestDataFrame(t)
+			tt.modify(df)
+			if got := len(df.LookupAll(tt.key)); got != tt.want {
+				t.Fatalf("LookupAll(%s) returned %d rows, want %d", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyKeepsIndex(t *testing.T) {
+	df := newIndexTestDataFrame(t)
+	newDf := df.Copy()
+
+	if _, ok := newDf.Lookup("2"); !ok {
+		t.Fatal("copy lost the index")
+	}
+	if err := newDf.SetValueE(1, "id", "1"); err == nil {
+		t.Fatal("copy lost the unique constraint")
+	}
+	newDf.AppendRecord([]string{"5", "eve"})
+	if _, ok := df.Lookup("5"); ok {
+		t.Fatal("copy shares index entries with the original")
+	}
+}
+
+func TestUniqueIndexRejectsDuplicates(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(df *DataFrame) error
+	}{
+		{"AppendRecordE", func(df *DataFrame) error {
+			_, err := df.AppendRecordE([]string{"1", "dup"})
+			return err
+		}},
+		{"AppendRowE", func(df *DataFrame) error {
+			_, err := df.AppendRowE(map[string]string{"id": "2", "name": "dup"})
+			return err
+		}},
+		{"SetRowsE", func(df *DataFrame) error {
+			return df.SetRowsE([][]string{{"7", "a"}, {"8", "b"}, {"7", "c"}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df := newIndexTestDataFrame(t)
+			if err := tt.modify(df); err == nil {
+				t.Fatal("duplicate key accepted")
+			}
+			if df.GetLength() != 2 || len(df.LookupAll("1")) != 1 || len(df.LookupAll("2")) != 1 {
+				t.Fatalf("rejected change modified df: %q", df.GetRows())
+			}
+		})
+	}
+
+	df := newIndexTestDataFrame(t)
+	if _, err := df.AppendRecordE([]string{"3", "carol"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := df.AppendRowE(map[string]string{"name": "no id"}); err != nil {
+		t.Fatalf("rows with an empty key are not indexed: %v", err)
+	}
+	if err := df.SetRowsE([][]string{{"5", "a"}, {"", "b"}, {"", "c"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrRejectsDuplicateKeys(t *testing.T) {
+	rows := [][]string{{"x1", "a-1", "1"}, {"x2", "b-2", "2"}, {"y1", "c-1", "1"}}
+	tests := []struct {
+		name   string
+		modify func(df *DataFrame) error
+	}{
+		{"Replace", func(df *DataFrame) error { return df.Str("id").Replace(`[xy]`, "") }},
+		{"Split", func(df *DataFrame) error { return df.Str("name").Split("-", []string{"name", "id"}) }},
+		{"Extract", func(df *DataFrame) error { return df.Str("name").Extract(`-(\d)`, "id") }},
+		{"Combine", func(df *DataFrame) error { return df.Combine("id", []string{"n"}, "{0}") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df := NewDataFrame("users")
+			df.SetHeads([]string{"id", "name", "n"})
+			df.SetRows(rows)
+			if err := df.SetUniqueIndex("id"); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.modify(df); err == nil {
+				t.Fatal("duplicate key accepted")
+			}
+			if got := df.GetRows(); !reflect.DeepEqual(got, rows) {
+				t.Fatalf("rejected change modified df: %q", got)
+			}
+			if _, ok := df.Lookup("x2"); !ok {
+				t.Fatal("index changed after rejected change")
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package pd
 
+import (
+	"sync"
+)
+
 type Excel struct {
 	SheetNames    []string
 	DataFramesMap map[string]*DataFrame
+	activeSheet   string
 }
 
 func NewExcel() *Excel {
@@ -12,18 +17,124 @@ func NewExcel() *Excel {
 	}
 }
 
+// DataFrame 按列存储，见 column，对外提供按行读写的方法
 type DataFrame struct {
 	sheetName    string
 	heads        []string
-	rows         [][]string
+	columns      []*column
+	length       int
 	headIndexMap map[string]int
+	index        *rowIndex
 }
 
 func NewDataFrame(sheetName string) *DataFrame {
 	return &DataFrame{
 		heads:        []string{},
-		rows:         [][]string{},
 		sheetName:    sheetName,
 		headIndexMap: make(map[string]int),
 	}
 }
+
+// Row df中一行的只读视图，读取的是df当前的内容
+type Row struct {
+	df    *DataFrame
+	index int
+}
+
+// SyncDataFrame 线程安全的df，所有读取方法返回的都是拷贝
+type SyncDataFrame struct {
+	df   *DataFrame
+	lock sync.RWMutex
+}
+
+func NewSyncDataFrame(sheetName string) *SyncDataFrame {
+	return &SyncDataFrame{
+		df: NewDataFrame(sheetName),
+	}
+}
+
+// Collector 从多个生产者的channel中收集行，按批次写入 SyncDataFrame
+type Collector struct {
+	sdf       *SyncDataFrame
+	rows      chan map[string]string
+	batchSize int
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCollector 创建Collector并启动后台写入，batchSize小于1时每行都会立即写入，使用完必须调用Close
+func NewCollector(sdf *SyncDataFrame, batchSize int) *Collector {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	c := &Collector{
+		sdf:       sdf,
+		rows:      make(chan map[string]string, batchSize),
+		batchSize: batchSize,
+		done:      make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Query df的链式查询，调用 Collect 时才会执行
+type Query struct {
+	df      *DataFrame
+	filters []func(row Row) bool
+	selects []string
+	orders  []queryOrder
+	limit   int
+	offset  int
+}
+
+type queryOrder struct {
+	head string
+	desc bool
+}
+
+// GroupBy 按列分组后的df，分组按第一次出现的顺序排列，组内保持原来的行顺序
+type GroupBy struct {
+	df     *DataFrame
+	heads  []string
+	groups [][]int
+}
+
+// Rolling 滑动窗口计算，窗口为当前行及之前的window-1行
+type Rolling struct {
+	df         *DataFrame
+	groups     [][]int
+	window     int
+	minPeriods int
+}
+
+// StrAccessor 对一列进行字符串操作，通过 DataFrame.Str 获取
+type StrAccessor struct {
+	df   *DataFrame
+	head string
+}
+
+// Schema 列的取值规则，导出时转为excel的下拉列表和数据验证，导入时通过 Validate 检查，模板和导入共用一份定义
+type Schema struct {
+	rules []*columnRule
+}
+
+type columnRule struct {
+	head     string
+	required bool
+	values   []string
+	hasRange bool
+	min, max float64
+}
+
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+// Anchor FillTemplate 中df写入的位置
+type Anchor struct {
+	// Cell 第一行数据写入的单元格，模板中这一行的样式会被复制到新插入的行
+	Cell string
+	DF   *DataFrame
+	// Heads 为true时先在Cell处写入表头，数据从下一行开始
+	Heads bool
+}
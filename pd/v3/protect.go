@@ -0,0 +1,62 @@
+package pd
+
+import (
+	"github.com/xuri/excelize/v2"
+)
+
+type readOptions struct {
+	password string
+}
+
+// ReadOption 读取或修改已有xlsx文件时的可选参数，用于 ReadExcelAllSheet、ReadSheetsWithOptions、AppendToSheet 和 FillTemplate
+type ReadOption func(*readOptions)
+
+// WithReadPassword 设置打开加密文件的密码
+func WithReadPassword(password string) ReadOption {
+	return func(o *readOptions) {
+		o.password = password
+	}
+}
+
+func newReadOptions(opts []ReadOption) *readOptions {
+	o := &readOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// openExcelFile 按读取选项打开xlsx文件
+func openExcelFile(src string, opts []ReadOption) (*excelize.File, error) {
+	o := newReadOptions(opts)
+	return excelize.OpenFile(src, excelize.Options{Password: o.password})
+}
+
+// WithPassword 保存为使用密码加密的文件，打开时需要输入密码
+func WithPassword(password string) SaveOption {
+	return func(o *saveOptions) {
+		o.password = password
+	}
+}
+
+// WithSheetProtection 保护所有sheet，只能选择和查看单元格，取消保护需要输入密码，password为空时不需要密码
+// 与 WithPassword 不同，文件本身不加密，适合分发只读的报表
+func WithSheetProtection(password string) SaveOption {
+	return func(o *saveOptions) {
+		o.protectSheets = true
+		o.protectPassword = password
+	}
+}
+
+// protectSheet 保护sheet，只允许选择单元格
+func protectSheet(file *excelize.File, sheetName string, password string) error {
+	opts := &excelize.SheetProtectionOptions{
+		Password:            password,
+		SelectLockedCells:   true,
+		SelectUnlockedCells: true,
+	}
+	if password != "" {
+		opts.AlgorithmName = "SHA-512"
+	}
+	return file.ProtectSheet(sheetName, opts)
+}
@@ -0,0 +1,56 @@
+package pd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEncryptedWorkbookOptions(t *testing.T) {
+	const password = "secret"
+	dir := t.TempDir()
+	src := filepath.Join(dir, "encrypted.xlsx")
+
+	df := NewDataFrame("data")
+	df.SetHeads([]string{"id", "name"})
+	df.SetRows([][]string{{"1", "alice"}})
+	e := NewExcel()
+	e.AppendSheet(df)
+	if err := e.SaveExcelAllSheet(src, WithPassword(password)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewExcel().ReadSheets(src, "data"); err == nil {
+		t.Fatal("ReadSheets without password succeeded")
+	}
+
+	more := NewDataFrame("data")
+	more.SetHeads([]string{"id", "name"})
+	more.SetRows([][]string{{"2", "bob"}})
+	if err := AppendToSheet(src, "data", more, WithReadPassword(password)); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "filled.xlsx")
+	err := FillTemplate(src, dst, map[string]Anchor{"data": {Cell: "A4", DF: more}}, WithReadPassword(password))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want [][]string
+	}{
+		{src, [][]string{{"1", "alice"}, {"2", "bob"}}},
+		{dst, [][]string{{"1", "alice"}, {"2", "bob"}, {"2", "bob"}}},
+	}
+	for _, tt := range tests {
+		read := NewExcel()
+		if err := read.ReadSheetsWithOptions(tt.path, []string{"data"}, WithReadPassword(password)); err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if got := read.DataFramesMap["data"].GetRows(); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: rows = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package pd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Query 创建链式查询，查询不会修改原df
+func (df *DataFrame) Query() *Query {
+	return &Query{df: df, limit: -1}
+}
+
+// Where 添加过滤条件，多个条件之间为且的关系
+func (q *Query) Where(filter func(row Row) bool) *Query {
+	q.filters = append(q.filters, filter)
+	return q
+}
+
+// WhereEq 过滤列的值等于value的行
+func (q *Query) WhereEq(head string, value string) *Query {
+	return q.Where(func(row Row) bool {
+		return row.Get(head) == value
+	})
+}
+
+// Select 指定结果中的列及顺序，不调用时保留所有列
+func (q *Query) Select(heads ...string) *Query {
+	q.selects = append(q.selects, heads...)
+	return q
+}
+
+// OrderBy 按列升序排序，多次调用时按调用顺序依次比较，两个值都是数字时按数值比较，数字排在非数字之前
+func (q *Query) OrderBy(head string) *Query {
+	q.orders = append(q.orders, queryOrder{head: head})
+	return q
+}
+
+// OrderByDesc 按列降序排序
+func (q *Query) OrderByDesc(head string) *Query {
+	q.orders = append(q.orders, queryOrder{head: head, desc: true})
+	return q
+}
+
+// Limit 最多返回n行，n小于0时不限制
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Offset 跳过前k行
+func (q *Query) Offset(k int) *Query {
+	q.offset = k
+	return q
+}
+
+// Count 返回满足条件的行数，不受 Limit 和 Offset 影响
+func (q *Query) Count() int {
+	count := 0
+	for i := 0; i < q.df.length; i++ {
+		if q.match(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// Collect 执行查询并返回新的df，结果的sheet名称与原df相同
+// 过滤和排序只处理行索引，最后只复制选中的行和列；没有排序时达到 Limit 后会提前结束扫描
+func (q *Query) Collect() (*DataFrame, error) {
+	df := q.df
+
+	positions, heads, err := q.selectPositions()
+	if err != nil {
+		return nil, err
+	}
+	orderPositions := make([]int, len(q.orders))
+	for i, order := range q.orders {
+		position, ok := df.headIndexMap[order.head]
+		if !ok {
+			return nil, fmt.Errorf("cannot find head %s", order.head)
+		}
+		orderPositions[i] = position
+	}
+
+	// 没有排序时只需要扫描到 offset+limit 行
+	stopAt := -1
+	if len(q.orders) == 0 && q.limit >= 0 {
+		stopAt = q.offset + q.limit
+	}
+
+	var rowIndexes []int
+	for i := 0; i < df.length; i++ {
+		if stopAt >= 0 && len(rowIndexes) >= stopAt {
+			break
+		}
+		if q.match(i) {
+			rowIndexes = append(rowIndexes, i)
+		}
+	}
+
+	if len(q.orders) > 0 {
+		rowIndexes = q.sortRows(rowIndexes, orderPositions)
+	}
+
+	if q.offset > 0 {
+		if q.offset >= len(rowIndexes) {
+			rowIndexes = nil
+		} else {
+			rowIndexes = rowIndexes[q.offset:]
+		}
+	}
+	if q.limit >= 0 && q.limit < len(rowIndexes) {
+		rowIndexes = rowIndexes[:q.limit]
+	}
+
+	result := NewDataFrame(df.sheetName)
+	result.SetHeads(heads)
+	result.columns = df.pickColumns(rowIndexes, positions)
+	result.length = len(rowIndexes)
+	result.grow(len(result.heads), 0)
+	return result, nil
+}
+
+func (q *Query) match(rowIndex int) bool {
+	row := q.df.GetRow(rowIndex)
+	for _, filter := range q.filters {
+		if !filter(row) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectPositions 返回结果中每一列在原df中的位置，没有 Select 时包括没有表头的列
+func (q *Query) selectPositions() ([]int, []string, error) {
+	df := q.df
+	if len(q.selects) == 0 {
+		positions := make([]int, df.recordWidth())
+		for i := range positions {
+			positions[i] = i
+		}
+		return positions, append([]string{}, df.heads...), nil
+	}
+
+	positions := make([]int, len(q.selects))
+	for i, head := range q.selects {
+		position, ok := df.headIndexMap[head]
+		if !ok {
+			return nil, nil, fmt.Errorf("cannot find head %s", head)
+		}
+		positions[i] = position
+	}
+	return positions, append([]string{}, q.selects...), nil
+}
+
+type sortKey struct {
+	text     string
+	number   float64
+	isNumber bool
+}
+
+func newSortKey(value string) sortKey {
+	number, err := strconv.ParseFloat(value, 64)
+	return sortKey{text: value, number: number, isNumber: err == nil && !math.IsNaN(number)}
+}
+
+// sortRows 按 OrderBy 排序行索引，每行的排序键只解析一次
+func (q *Query) sortRows(rowIndexes []int, orderPositions []int) []int {
+	type sortRow struct {
+		index int
+		keys  []sortKey
+	}
+	rows := make([]sortRow, len(rowIndexes))
+	for i, rowIndex := range rowIndexes {
+		keys := make([]sortKey, len(orderPositions))
+		for j, position := range orderPositions {
+			keys[j] = newSortKey(q.df.at(rowIndex, position))
+		}
+		rows[i] = sortRow{index: rowIndex, keys: keys}
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		for i, order := range q.orders {
+			cmp := compareSortKeys(rows[a].keys[i], rows[b].keys[i])
+			if cmp == 0 {
+				continue
+			}
+			if order.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	sorted := make([]int, len(rows))
+	for i, row := range rows {
+		sorted[i] = row.index
+	}
+	return sorted
+}
+
+// compareValues 两个值都是数字时按数值比较，都不是数字时按字符串比较，数字排在所有非数字之前
+// 这样同一列中数字和文本混合时排序结果仍然是确定的，NaN按非数字处理
+func compareValues(a, b string) int {
+	return compareSortKeys(newSortKey(a), newSortKey(b))
+}
+
+func compareSortKeys(a, b sortKey) int {
+	switch {
+	case a.isNumber && b.isNumber:
+		switch {
+		case a.number < b.number:
+			return -1
+		case a.number > b.number:
+			return 1
+		default:
+			return 0
+		}
+	case a.isNumber:
+		return -1
+	case b.isNumber:
+		return 1
+	}
+
+	switch {
+	case a.text < b.text:
+		return -1
+	case a.text > b.text:
+		return 1
+	default:
+		return 0
+	}
+}
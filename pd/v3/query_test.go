@@ -0,0 +1,84 @@
+package pd
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCompareValuesIsTransitive(t *testing.T) {
+	// 按旧的规则 "10" < "9a" < "9" < "10"，排序结果取决于输入顺序
+	values := []string{"9", "10", "9a", "", "abc", "-1", "1e3", "NaN", "2"}
+	want := []string{"-1", "2", "9", "10", "1e3", "", "9a", "NaN", "abc"}
+
+	for _, start := range []int{0, 3, 5} {
+		input := append(append([]string{}, values[start:]...), values[:start]...)
+		sort.SliceStable(input, func(i, j int) bool {
+			return compareValues(input[i], input[j]) < 0
+		})
+		if !reflect.DeepEqual(input, want) {
+			t.Fatalf("sorted = %q, want %q", input, want)
+		}
+	}
+
+	for _, a := range values {
+		for _, b := range values {
+			for _, c := range values {
+				if compareValues(a, b) < 0 && compareValues(b, c) < 0 && compareValues(a, c) >= 0 {
+					t.Fatalf("%q < %q < %q but not %q < %q", a, b, c, a, c)
+				}
+			}
+		}
+	}
+}
+
+func TestRankMixedValues(t *testing.T) {
+	df := NewDataFrame("rank")
+	df.SetHeads([]string{"score"})
+	df.SetRows([][]string{{"10"}, {"9a"}, {"9"}, {"10"}})
+
+	if err := df.Rank("score", "rank", false); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, df.GetLength())
+	for i := range got {
+		got[i] = df.GetValue(i, "rank")
+	}
+	if want := []string{"2", "4", "1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("rank = %q, want %q", got, want)
+	}
+}
+
+func TestQueryCollect(t *testing.T) {
+	df := NewDataFrame("query")
+	df.SetHeads([]string{"name", "group", "score"})
+	df.SetRows([][]string{
+		{"a", "x", "10"},
+		{"b", "y", "9"},
+		{"c", "x", "9a"},
+		{"d", "y", "10"},
+		{"e", "x", "2"},
+	})
+
+	result, err := df.Query().
+		Where(func(row Row) bool { return row.Get("name") != "e" }).
+		OrderBy("group").
+		OrderByDesc("score").
+		Select("score", "name").
+		Offset(1).
+		Limit(2).
+		Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"score", "name"}; !reflect.DeepEqual(result.GetHeads(), want) {
+		t.Fatalf("heads = %q, want %q", result.GetHeads(), want)
+	}
+	if want := [][]string{{"10", "a"}, {"10", "d"}}; !reflect.DeepEqual(result.GetRows(), want) {
+		t.Fatalf("rows = %q, want %q", result.GetRows(), want)
+	}
+
+	if _, err := df.Query().OrderBy("missing").Collect(); err == nil {
+		t.Fatal("OrderBy on a missing head succeeded")
+	}
+}
@@ -0,0 +1,175 @@
+package pd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+)
+
+// headPositions 返回表头的位置，表头不存在时返回错误
+func (df *DataFrame) headPositions(heads []string) ([]int, error) {
+	positions := make([]int, len(heads))
+	for i, head := range heads {
+		position, ok := df.headIndexMap[head]
+		if !ok {
+			return nil, fmt.Errorf("cannot find head %s", head)
+		}
+		positions[i] = position
+	}
+	return positions, nil
+}
+
+// uniqueHeads 由数据生成表头时使用，空的表头改为excel的列名，重复的表头加上 _2、_3 后缀，与 pd/sql 的规则相同
+func uniqueHeads(heads []string) []string {
+	used := make(map[string]bool, len(heads))
+	result := make([]string, len(heads))
+	for i, head := range heads {
+		if head == "" {
+			head, _ = excelize.ColumnNumberToName(i + 1)
+		}
+		name := head
+		for n := 2; used[name]; n++ {
+			name = head + "_" + strconv.Itoa(n)
+		}
+		used[name] = true
+		result[i] = name
+	}
+	return result
+}
+
+// Pivot 长表转宽表，index列的值组合作为行，columns列的每个值作为新列，values列的值经agg聚合后填入
+// 行和新列都按第一次出现的顺序排列，没有值的单元格为空；agg为nil时同一位置出现多个值会返回错误
+func (df *DataFrame) Pivot(index []string, columns string, values string, agg AggFunc) (*DataFrame, error) {
+	indexPositions, err := df.headPositions(index)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := df.headPositions([]string{columns, values})
+	if err != nil {
+		return nil, err
+	}
+	columnPosition, valuePosition := positions[0], positions[1]
+
+	var rowKeys, columnKeys []string
+	seenColumns := map[string]bool{}
+	rowValues := map[string][]string{}
+	cells := map[string]map[string][]string{}
+	for r := 0; r < df.length; r++ {
+		indexValues := make([]string, len(indexPositions))
+		for i, position := range indexPositions {
+			indexValues[i] = df.at(r, position)
+		}
+		rowKey := strings.Join(indexValues, "\x1F")
+		if _, ok := cells[rowKey]; !ok {
+			rowKeys = append(rowKeys, rowKey)
+			rowValues[rowKey] = indexValues
+			cells[rowKey] = map[string][]string{}
+		}
+
+		columnKey := df.at(r, columnPosition)
+		if !seenColumns[columnKey] {
+			if lo.Contains(index, columnKey) {
+				return nil, fmt.Errorf("pivot column %s conflicts with index head", columnKey)
+			}
+			seenColumns[columnKey] = true
+			columnKeys = append(columnKeys, columnKey)
+		}
+		cells[rowKey][columnKey] = append(cells[rowKey][columnKey], df.at(r, valuePosition))
+	}
+
+	rows := make([][]string, len(rowKeys))
+	for i, rowKey := range rowKeys {
+		row := append([]string{}, rowValues[rowKey]...)
+		for _, columnKey := range columnKeys {
+			group := cells[rowKey][columnKey]
+			switch {
+			case len(group) == 0:
+				row = append(row, "")
+			case agg != nil:
+				row = append(row, agg(group))
+			case len(group) == 1:
+				row = append(row, group[0])
+			default:
+				return nil, fmt.Errorf("duplicate entries for index %v and column %s, agg is required",
+					rowValues[rowKey], columnKey)
+			}
+		}
+		rows[i] = row
+	}
+
+	result := NewDataFrame(df.sheetName)
+	result.SetHeads(append(append([]string{}, index...), columnKeys...))
+	result.SetRows(rows)
+	return result, nil
+}
+
+// Melt 宽表转长表，每行的每个valueVars列展开为一行，结果的列为idVars、variable和value
+// valueVars为空时使用idVars之外的所有列
+func (df *DataFrame) Melt(idVars []string, valueVars []string) (*DataFrame, error) {
+	if len(valueVars) == 0 {
+		valueVars = lo.Without(df.heads, idVars...)
+	}
+	for _, head := range []string{"variable", "value"} {
+		if lo.Contains(idVars, head) {
+			return nil, fmt.Errorf("id head %s conflicts with melt output head", head)
+		}
+	}
+
+	idPositions, err := df.headPositions(idVars)
+	if err != nil {
+		return nil, err
+	}
+	valuePositions, err := df.headPositions(valueVars)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, df.length*len(valueVars))
+	for r := 0; r < df.length; r++ {
+		for i, valuePosition := range valuePositions {
+			newRow := make([]string, 0, len(idPositions)+2)
+			for _, idPosition := range idPositions {
+				newRow = append(newRow, df.at(r, idPosition))
+			}
+			newRow = append(newRow, valueVars[i], df.at(r, valuePosition))
+			rows = append(rows, newRow)
+		}
+	}
+
+	result := NewDataFrame(df.sheetName)
+	result.SetHeads(append(append([]string{}, idVars...), "variable", "value"))
+	result.SetRows(rows)
+	return result, nil
+}
+
+// Transpose 转置，第一列的值成为新的表头，其余列名成为新的第一列，第一个表头保持不变
+// 第一列中为空的值使用excel的列名作为表头，重复的值加上 _2、_3 后缀，例如 sales、sales_2
+func (df *DataFrame) Transpose() (*DataFrame, error) {
+	if len(df.heads) == 0 {
+		return nil, fmt.Errorf("dataframe has no heads")
+	}
+
+	heads := make([]string, 0, df.length+1)
+	heads = append(heads, df.heads[0])
+	for r := 0; r < df.length; r++ {
+		heads = append(heads, df.at(r, 0))
+	}
+
+	rows := make([][]string, 0, len(df.heads)-1)
+	for j := 1; j < len(df.heads); j++ {
+		newRow := make([]string, 0, df.length+1)
+		newRow = append(newRow, df.heads[j])
+		for r := 0; r < df.length; r++ {
+			newRow = append(newRow, df.at(r, j))
+		}
+		rows = append(rows, newRow)
+	}
+
+	result := NewDataFrame(df.sheetName)
+	result.SetHeads(uniqueHeads(heads))
+	result.SetRows(rows)
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package pd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransposeUniqueHeads(t *testing.T) {
+	df := NewDataFrame("report")
+	df.SetHeads([]string{"metric", "q1", "q2"})
+	df.SetRows([][]string{{"sales", "1", "2"}, {"sales", "3", "4"}, {"", "5", "6"}})
+
+	result, err := df.Transpose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"metric", "sales", "sales_2", "D"}; !reflect.DeepEqual(result.GetHeads(), want) {
+		t.Fatalf("heads = %q, want %q", result.GetHeads(), want)
+	}
+	if got := result.GetValue(0, "sales"); got != "1" {
+		t.Fatalf("sales = %s, want 1", got)
+	}
+	if got := result.GetValue(0, "sales_2"); got != "3" {
+		t.Fatalf("sales_2 = %s, want 3", got)
+	}
+}
+
+func TestUniqueHeads(t *testing.T) {
+	tests := []struct {
+		heads []string
+		want  []string
+	}{
+		{[]string{"a", "b"}, []string{"a", "b"}},
+		{[]string{"a", "a", "a"}, []string{"a", "a_2", "a_3"}},
+		{[]string{"a", "a_2", "a"}, []string{"a", "a_2", "a_3"}},
+		{[]string{"", "B", ""}, []string{"A", "B", "C"}},
+		{[]string{"B", ""}, []string{"B", "B_2"}},
+	}
+	for _, tt := range tests {
+		if got := uniqueHeads(tt.heads); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("uniqueHeads(%q) = %q, want %q", tt.heads, got, tt.want)
+		}
+	}
+}
+
+func TestPivotKeepsColumnOrder(t *testing.T) {
+	df := NewDataFrame("sales")
+	df.SetHeads([]string{"region", "month", "amount"})
+	df.SetRows([][]string{
+		{"north", "feb", "1"},
+		{"south", "jan", "2"},
+		{"north", "jan", "3"},
+		{"north", "feb", "4"},
+	})
+
+	result, err := df.Pivot([]string{"region"}, "month", "amount", AggSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"region", "feb", "jan"}, {"north", "5", "3"}, {"south", "", "2"}}
+	if got := append([][]string{result.GetHeads()}, result.GetRows()...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("pivot = %q, want %q", got, want)
+	}
+
+	if _, err := df.Pivot([]string{"month"}, "region", "amount", nil); err == nil {
+		t.Fatal("duplicate entries without agg accepted")
+	}
+}
@@ -0,0 +1,39 @@
+package pd
+
+// GetRow 返回索引处的行，不检查索引是否超出范围
+func (df *DataFrame) GetRow(rowIndex int) Row {
+	return Row{df: df, index: rowIndex}
+}
+
+// Index 返回行在df中的索引
+func (r Row) Index() int {
+	return r.index
+}
+
+// Get 返回行中列的值，接受head的类型为string或int，列不存在时返回空字符串
+func (r Row) Get(head any) string {
+	return r.df.GetValue(r.index, head)
+}
+
+// GetE 返回行中列的值，列不存在或索引超出范围时返回错误
+func (r Row) GetE(head any) (string, error) {
+	return r.df.GetValueE(r.index, head)
+}
+
+// Values 返回行的拷贝，长度与表头一致
+func (r Row) Values() []string {
+	values := make([]string, len(r.df.heads))
+	for i := range values {
+		values[i] = r.df.at(r.index, i)
+	}
+	return values
+}
+
+// ToMap 返回以表头为键的map
+func (r Row) ToMap() map[string]string {
+	m := make(map[string]string, len(r.df.heads))
+	for i, value := range r.Values() {
+		m[r.df.heads[i]] = value
+	}
+	return m
+}
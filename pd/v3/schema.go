@@ -0,0 +1,310 @@
+package pd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// dropdownListSheet 下拉列表的值超过excel的长度限制时，值写入这个隐藏的sheet中，与数据sheet重名时加上后缀
+	dropdownListSheet = "_lists"
+	// defaultHighlightColor 条件格式默认的填充颜色
+	defaultHighlightColor = "FFC7CE"
+)
+
+// rule 返回列的规则，不存在时创建
+func (s *Schema) rule(head string) *columnRule {
+	for _, rule := range s.rules {
+		if rule.head == head {
+			return rule
+		}
+	}
+	rule := &columnRule{head: head}
+	s.rules = append(s.rules, rule)
+	return rule
+}
+
+// Enum 列的值只能是values中的一个，导出时为下拉列表
+func (s *Schema) Enum(head string, values ...string) *Schema {
+	s.rule(head).values = append([]string{}, values...)
+	return s
+}
+
+// Range 列的值必须是[min, max]范围内的数字，与 Enum 同时使用时导出为下拉列表，范围只用于检查和高亮
+func (s *Schema) Range(head string, min, max float64) *Schema {
+	rule := s.rule(head)
+	rule.hasRange = true
+	rule.min, rule.max = min, max
+	return s
+}
+
+// Required 列的值不能为空
+func (s *Schema) Required(head string) *Schema {
+	s.rule(head).required = true
+	return s
+}
+
+// Validate 检查df是否满足所有规则，空值只检查 Required，不满足的单元格以 *ApplyError 返回
+func (s *Schema) Validate(df *DataFrame) error {
+	var rowErrors []*RowError
+	for _, rule := range s.rules {
+		position, ok := df.headIndexMap[rule.head]
+		if !ok {
+			return fmt.Errorf("cannot find head %s", rule.head)
+		}
+		for i := 0; i < df.length; i++ {
+			if err := rule.check(df.at(i, position)); err != nil {
+				rowErrors = append(rowErrors, &RowError{Row: i, Err: err})
+			}
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return &ApplyError{Errors: rowErrors}
+	}
+	return nil
+}
+
+func (rule *columnRule) check(value string) error {
+	if strings.TrimSpace(value) == "" {
+		if rule.required {
+			return fmt.Errorf("head %s is required", rule.head)
+		}
+		return nil
+	}
+
+	if len(rule.values) > 0 && !lo.Contains(rule.values, value) {
+		return fmt.Errorf("head %s: value %q is not in %v", rule.head, value, rule.values)
+	}
+	if rule.hasRange {
+		f, ok := parseNumber(value)
+		if !ok {
+			return fmt.Errorf("head %s: value %q is not a number", rule.head, value)
+		}
+		if f < rule.min || f > rule.max {
+			return fmt.Errorf("head %s: value %s is out of range [%v, %v]", rule.head, value, rule.min, rule.max)
+		}
+	}
+	return nil
+}
+
+// sheetRule 保存时对一个sheet添加的数据验证或条件格式，rows为数据的行数，不包括表头
+type sheetRule func(file *excelize.File, sheetName string, heads []string, rows int) error
+
+func addSheetRule(o *saveOptions, sheetName string, rule sheetRule) {
+	if o.sheetRules == nil {
+		o.sheetRules = map[string][]sheetRule{}
+	}
+	o.sheetRules[sheetName] = append(o.sheetRules[sheetName], rule)
+}
+
+// WithSchema 按schema为sheet添加数据验证，Enum为下拉列表，Range为数字范围，
+// 已有的数据中不满足规则的值会被高亮，Required列中的空值也会被高亮
+// sheet被拆分时每个拆分后的sheet都会添加
+func WithSchema(sheetName string, schema *Schema) SaveOption {
+	return func(o *saveOptions) {
+		addSheetRule(o, sheetName, func(file *excelize.File, name string, heads []string, rows int) error {
+			for _, rule := range schema.rules {
+				if err := rule.apply(file, name, heads, rows, o.listSheet); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// WithDropdown 为sheet中的列添加下拉列表，只能选择values中的值
+func WithDropdown(sheetName, head string, values ...string) SaveOption {
+	return func(o *saveOptions) {
+		addSheetRule(o, sheetName, func(file *excelize.File, name string, heads []string, rows int) error {
+			column, err := columnName(heads, head)
+			if err != nil {
+				return err
+			}
+			_, err = addDropdown(file, name, column, head, values, o.listSheet)
+			return err
+		})
+	}
+}
+
+// WithHighlight 高亮sheet中列的值满足 value criteria threshold 的单元格，例如 criteria 为 ">" 时高亮大于threshold的值
+// criteria 支持 >、>=、<、<=、=、<>，非数字的值不会被高亮，color为空时使用浅红色
+func WithHighlight(sheetName, head string, criteria string, threshold float64, color string) SaveOption {
+	return func(o *saveOptions) {
+		addSheetRule(o, sheetName, func(file *excelize.File, name string, heads []string, rows int) error {
+			switch criteria {
+			case ">", ">=", "<", "<=", "=", "<>":
+			default:
+				return fmt.Errorf("unsupported highlight criteria %s", criteria)
+			}
+			column, err := columnName(heads, head)
+			if err != nil {
+				return err
+			}
+			cell := column + "2"
+			formula := fmt.Sprintf("AND(ISNUMBER(--%s),--%s%s%s)", cell, cell, criteria, formatNumber(threshold))
+			return addHighlight(file, name, column, excelize.TotalRows, formula, color)
+		})
+	}
+}
+
+// apply 将规则添加到sheet中，一个单元格只能有一个数据验证，同时有 Enum 和 Range 时只添加下拉列表
+func (rule *columnRule) apply(file *excelize.File, sheetName string, heads []string, rows int, listSheet string) error {
+	column, err := columnName(heads, rule.head)
+	if err != nil {
+		return err
+	}
+	cell := column + "2"
+
+	if len(rule.values) > 0 {
+		listRef, err := addDropdown(file, sheetName, column, rule.head, rule.values, listSheet)
+		if err != nil {
+			return err
+		}
+
+		// 单元格转为文本后比较，使数字与列表中的值能够匹配
+		var formula string
+		if listRef != "" {
+			formula = fmt.Sprintf("AND(%s<>\"\",COUNTIF(%s,%s&\"\")=0)", cell, listRef, cell)
+		} else {
+			conditions := make([]string, len(rule.values))
+			for i, value := range rule.values {
+				conditions[i] = fmt.Sprintf("%s&\"\"<>%s", cell, formulaString(value))
+			}
+			formula = fmt.Sprintf("AND(%s<>\"\",%s)", cell, strings.Join(conditions, ","))
+		}
+		if err := addHighlight(file, sheetName, column, excelize.TotalRows, formula, ""); err != nil {
+			return err
+		}
+	}
+
+	if rule.hasRange {
+		if len(rule.values) == 0 {
+			dv := excelize.NewDataValidation(true)
+			dv.Sqref = columnRange(column, excelize.TotalRows)
+			if err := dv.SetRange(rule.min, rule.max, excelize.DataValidationTypeDecimal, excelize.DataValidationOperatorBetween); err != nil {
+				return err
+			}
+			dv.SetError(excelize.DataValidationErrorStyleStop, rule.head,
+				fmt.Sprintf("%s must be between %v and %v", rule.head, rule.min, rule.max))
+			if err := addDataValidation(file, sheetName, dv); err != nil {
+				return err
+			}
+		}
+
+		formula := fmt.Sprintf("AND(%s<>\"\",OR(NOT(ISNUMBER(--%s)),--%s<%s,--%s>%s))",
+			cell, cell, cell, formatNumber(rule.min), cell, formatNumber(rule.max))
+		if err := addHighlight(file, sheetName, column, excelize.TotalRows, formula, ""); err != nil {
+			return err
+		}
+	}
+
+	// 模板中还没有填写的行不算缺失，只高亮已有数据中的空值
+	if rule.required && rows > 0 {
+		formula := fmt.Sprintf("LEN(TRIM(%s))=0", cell)
+		if err := addHighlight(file, sheetName, column, rows+1, formula, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addDropdown 添加下拉列表，值的总长度超过excel的限制时，值写入隐藏的listSheet中再引用，并返回引用的范围
+func addDropdown(file *excelize.File, sheetName, column, head string, values []string, listSheet string) (string, error) {
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = columnRange(column, excelize.TotalRows)
+
+	var listRef string
+	if err := dv.SetDropList(values); err != nil {
+		if listRef, err = writeDropdownList(file, listSheet, values); err != nil {
+			return "", err
+		}
+		dv.SetSqrefDropList(listRef)
+	}
+	dv.SetError(excelize.DataValidationErrorStyleStop, head, fmt.Sprintf("%s must be one of the listed values", head))
+	return listRef, addDataValidation(file, sheetName, dv)
+}
+
+// addDataValidation 添加数据验证，excel不允许同一单元格有多个数据验证，范围重叠时返回错误
+func addDataValidation(file *excelize.File, sheetName string, dv *excelize.DataValidation) error {
+	existing, err := file.GetDataValidations(sheetName)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.Sqref == dv.Sqref {
+			return fmt.Errorf("cells %s already have a data validation", dv.Sqref)
+		}
+	}
+	return file.AddDataValidation(sheetName, dv)
+}
+
+// writeDropdownList 将值写入隐藏的listSheet的下一个空列，返回引用的范围
+func writeDropdownList(file *excelize.File, listSheet string, values []string) (string, error) {
+	index, err := file.GetSheetIndex(listSheet)
+	if err != nil {
+		return "", err
+	}
+	if index < 0 {
+		if _, err := file.NewSheet(listSheet); err != nil {
+			return "", err
+		}
+		if err := file.SetSheetVisible(listSheet, false); err != nil {
+			return "", err
+		}
+	}
+
+	cols, err := file.GetCols(listSheet)
+	if err != nil {
+		return "", err
+	}
+	column, err := excelize.ColumnNumberToName(len(cols) + 1)
+	if err != nil {
+		return "", err
+	}
+	if err := file.SetSheetCol(listSheet, column+"1", &values); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("'%s'!$%s$1:$%s$%d", listSheet, column, column, len(values)), nil
+}
+
+// addHighlight 对列的第2行到lastRow行添加公式条件格式，公式中的单元格为第2行
+func addHighlight(file *excelize.File, sheetName, column string, lastRow int, formula string, color string) error {
+	if color == "" {
+		color = defaultHighlightColor
+	}
+	style, err := file.NewConditionalStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{color}},
+	})
+	if err != nil {
+		return err
+	}
+	return file.SetConditionalFormat(sheetName, columnRange(column, lastRow), []excelize.ConditionalFormatOptions{
+		{Type: "formula", Criteria: formula, Format: style},
+	})
+}
+
+// columnName 返回表头对应的excel列名，例如 A、B
+func columnName(heads []string, head string) (string, error) {
+	for i, h := range heads {
+		if h == head {
+			return excelize.ColumnNumberToName(i + 1)
+		}
+	}
+	return "", fmt.Errorf("cannot find head %s", head)
+}
+
+// columnRange 列中表头以下到lastRow的范围，例如 B2:B100
+func columnRange(column string, lastRow int) string {
+	return column + "2:" + column + strconv.Itoa(lastRow)
+}
+
+// formulaString 转为excel公式中的字符串常量
+func formulaString(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
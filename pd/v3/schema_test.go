@@ -0,0 +1,143 @@
+package pd
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// longValues 总长度超过下拉列表255个字符的限制，需要写入隐藏sheet
+func longValues() []string {
+	values := make([]string, 40)
+	for i := range values {
+		values[i] = fmt.Sprintf("category-%02d", i)
+	}
+	return values
+}
+
+func TestSchemaListSheetName(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "schema.xlsx")
+
+	data := NewDataFrame("data")
+	data.SetHeads([]string{"category"})
+	data.SetRows([][]string{{"category-01"}})
+	lists := NewDataFrame(dropdownListSheet)
+	lists.SetHeads([]string{"note"})
+	lists.SetRows([][]string{{"user data"}})
+
+	e := NewExcel()
+	e.AppendSheet(data, lists)
+	schema := NewSchema().Enum("category", longValues()...)
+	if err := e.SaveExcelAllSheet(dst, WithSchema("data", schema)); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := excelize.OpenFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	want := []string{"data", dropdownListSheet, dropdownListSheet + "_2"}
+	if got := file.GetSheetList(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("sheets = %q, want %q", got, want)
+	}
+	if rows, _ := file.GetRows(dropdownListSheet); len(rows) != 2 || rows[1][0] != "user data" {
+		t.Fatalf("user sheet %s was modified: %q", dropdownListSheet, rows)
+	}
+	dvs, err := file.GetDataValidations("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dvs) != 1 || !strings.Contains(dvs[0].Formula1, dropdownListSheet+"_2") {
+		t.Fatalf("data validations = %+v", dvs)
+	}
+}
+
+func TestSchemaDataValidations(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    func() []SaveOption
+		wantDvs int
+		wantErr string
+	}{
+		{
+			name: "enum and range are merged",
+			opts: func() []SaveOption {
+				return []SaveOption{WithSchema("data", NewSchema().Enum("score", "1", "2", "3").Range("score", 1, 3))}
+			},
+			wantDvs: 1,
+		},
+		{
+			name: "range only",
+			opts: func() []SaveOption {
+				return []SaveOption{WithSchema("data", NewSchema().Range("score", 1, 3))}
+			},
+			wantDvs: 1,
+		},
+		{
+			name: "dropdown on a column with range",
+			opts: func() []SaveOption {
+				return []SaveOption{
+					WithSchema("data", NewSchema().Range("score", 1, 3)),
+					WithDropdown("data", "score", "1", "2"),
+				}
+			},
+			wantErr: "already have a data validation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "schema.xlsx")
+			df := NewDataFrame("data")
+			df.SetHeads([]string{"score"})
+			df.SetRows([][]string{{"2"}})
+			e := NewExcel()
+			e.AppendSheet(df)
+
+			err := e.SaveExcelAllSheet(dst, tt.opts()...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := excelize.OpenFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			dvs, err := file.GetDataValidations("data")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(dvs) != tt.wantDvs {
+				t.Fatalf("got %d data validations, want %d", len(dvs), tt.wantDvs)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	df := NewDataFrame("data")
+	df.SetHeads([]string{"level", "score"})
+	df.SetRows([][]string{{"low", "5"}, {"bad", "50"}, {"", "x"}})
+
+	schema := NewSchema().Enum("level", "low", "high").Required("level").Range("score", 0, 10)
+	err := schema.Validate(df)
+	applyErr, ok := err.(*ApplyError)
+	if !ok {
+		t.Fatalf("err = %v, want *ApplyError", err)
+	}
+	if len(applyErr.Errors) != 4 {
+		t.Fatalf("got %d row errors, want 4: %v", len(applyErr.Errors), err)
+	}
+}
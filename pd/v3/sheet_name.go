@@ -0,0 +1,162 @@
+package pd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// maxSheetNameLength excel中sheet名称的最大字符数
+	maxSheetNameLength = excelize.MaxSheetNameLength
+	// maxSheetDataRows 一个sheet中除表头外最多能写入的行数
+	maxSheetDataRows = excelize.TotalRows - 1
+)
+
+type saveOptions struct {
+	splitSheets  bool
+	maxSheetRows int
+	report       *SaveReport
+
+	password        string
+	protectSheets   bool
+	protectPassword string
+	// sheetRules 原sheet名称到数据验证和条件格式的映射
+	sheetRules map[string][]sheetRule
+	// listSheet 保存下拉列表的隐藏sheet的名称，保存时确定，不会与数据sheet重名
+	listSheet string
+}
+
+// SaveOption SaveExcelAllSheet的可选参数
+type SaveOption func(*saveOptions)
+
+// SaveReport 保存时对sheet所做的修改
+type SaveReport struct {
+	// RenamedSheets 原sheet名称到修正后名称的映射，只包含被修改的sheet
+	RenamedSheets map[string]string
+	// SplitSheets 被拆分的sheet名称到拆分后各sheet名称的映射
+	SplitSheets map[string][]string
+}
+
+// WithSplitSheets 超过excel行数限制的sheet拆分为 Name_1、Name_2... 多个sheet，每个sheet都带有表头
+func WithSplitSheets() SaveOption {
+	return func(o *saveOptions) {
+		o.splitSheets = true
+	}
+}
+
+// WithMaxSheetRows 设置每个sheet除表头外的最大行数并开启拆分，不能超过excel的限制
+func WithMaxSheetRows(maxRows int) SaveOption {
+	return func(o *saveOptions) {
+		o.splitSheets = true
+		if maxRows > 0 && maxRows < maxSheetDataRows {
+			o.maxSheetRows = maxRows
+		}
+	}
+}
+
+// WithSaveReport 保存后将sheet名称的修正和拆分情况写入report
+func WithSaveReport(report *SaveReport) SaveOption {
+	return func(o *saveOptions) {
+		o.report = report
+	}
+}
+
+func newSaveOptions(opts []SaveOption) *saveOptions {
+	o := &saveOptions{maxSheetRows: maxSheetDataRows}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// sheetPlan 保存时一个sheet的写入计划
+type sheetPlan struct {
+	name   string
+	source string
+	df     *DataFrame
+	rows   [][]string
+}
+
+// planSheets 修正sheet名称并拆分超长的sheet，生成写入计划
+func (e *Excel) planSheets(o *saveOptions) ([]sheetPlan, error) {
+	report := &SaveReport{
+		RenamedSheets: map[string]string{},
+		SplitSheets:   map[string][]string{},
+	}
+	used := map[string]bool{}
+
+	var plans []sheetPlan
+	for _, sheetName := range e.orderedSheetNames() {
+		df := e.DataFramesMap[sheetName]
+		rows := df.GetRows()
+
+		if len(rows) <= o.maxSheetRows {
+			name := uniqueSheetName(SanitizeSheetName(sheetName), used)
+			if name != sheetName {
+				report.RenamedSheets[sheetName] = name
+			}
+			plans = append(plans, sheetPlan{name: name, source: sheetName, df: df, rows: rows})
+			continue
+		}
+
+		if !o.splitSheets {
+			return nil, fmt.Errorf("sheet %s has %d rows, exceeds the limit of %d", sheetName, len(rows), o.maxSheetRows)
+		}
+
+		for part, start := 1, 0; start < len(rows); part, start = part+1, start+o.maxSheetRows {
+			end := start + o.maxSheetRows
+			if end > len(rows) {
+				end = len(rows)
+			}
+			suffix := "_" + strconv.Itoa(part)
+			name := uniqueSheetName(truncateSheetName(SanitizeSheetName(sheetName), suffix)+suffix, used)
+			report.SplitSheets[sheetName] = append(report.SplitSheets[sheetName], name)
+			plans = append(plans, sheetPlan{name: name, source: sheetName, df: df, rows: rows[start:end]})
+		}
+	}
+
+	if o.report != nil {
+		*o.report = *report
+	}
+	return plans, nil
+}
+
+// SanitizeSheetName 将sheet名称修正为excel允许的格式：替换 []:*?/\ 为下划线，去掉首尾的单引号，截断到31个字符
+func SanitizeSheetName(sheetName string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, sheetName)
+	name = strings.Trim(name, "'")
+	name = truncateSheetName(name, "")
+	if strings.TrimSpace(name) == "" {
+		return "Sheet"
+	}
+	return name
+}
+
+// truncateSheetName 截断名称，保证加上suffix后不超过excel的长度限制
+func truncateSheetName(name string, suffix string) string {
+	maxLength := maxSheetNameLength - utf8.RuneCountInString(suffix)
+	if utf8.RuneCountInString(name) <= maxLength {
+		return name
+	}
+	return strings.TrimRight(string([]rune(name)[:maxLength]), "'")
+}
+
+// uniqueSheetName excel的sheet名称不区分大小写，重复时追加 _2、_3... 后缀
+func uniqueSheetName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[strings.ToLower(unique)]; i++ {
+		suffix := "_" + strconv.Itoa(i)
+		unique = truncateSheetName(name, suffix) + suffix
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
@@ -0,0 +1,124 @@
+package pd
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// selectRows 返回只包含指定行的新df，表头和sheet名称与原df相同，行会被拷贝
+func (df *DataFrame) selectRows(rowIndexes []int) *DataFrame {
+	newDf := NewDataFrame(df.sheetName)
+	newDf.SetHeads(append([]string{}, df.heads...))
+	newDf.columns = df.pickRows(rowIndexes)
+	newDf.length = len(rowIndexes)
+	newDf.grow(len(newDf.heads), 0)
+	return newDf
+}
+
+// Slice 返回[start, end)范围内的行，超出范围的部分会被忽略
+func (df *DataFrame) Slice(start, end int) *DataFrame {
+	if start < 0 {
+		start = 0
+	}
+	if end > df.length {
+		end = df.length
+	}
+
+	var rowIndexes []int
+	for i := start; i < end; i++ {
+		rowIndexes = append(rowIndexes, i)
+	}
+	return df.selectRows(rowIndexes)
+}
+
+// Head 返回前n行
+func (df *DataFrame) Head(n int) *DataFrame {
+	return df.Slice(0, n)
+}
+
+// Tail 返回最后n行
+func (df *DataFrame) Tail(n int) *DataFrame {
+	if n < 0 {
+		n = 0
+	}
+	return df.Slice(df.length-n, df.length)
+}
+
+// Sample 不放回地随机抽取n行，相同的seed得到相同的结果，结果保持原来的行顺序
+func (df *DataFrame) Sample(n int, seed int64) *DataFrame {
+	return df.selectRows(sampleIndexes(allIndexes(df.length), n, rand.New(rand.NewSource(seed))))
+}
+
+// SampleFrac 按比例随机抽取行，行数四舍五入
+func (df *DataFrame) SampleFrac(frac float64, seed int64) *DataFrame {
+	return df.Sample(fracCount(df.length, frac), seed)
+}
+
+// StratifiedSample 按列的值分层，每层按比例随机抽取，非空的层至少抽取一行，结果保持原来的行顺序
+func (df *DataFrame) StratifiedSample(head string, frac float64, seed int64) (*DataFrame, error) {
+	index, ok := df.headIndexMap[head]
+	if !ok {
+		return nil, fmt.Errorf("cannot find head %s", head)
+	}
+
+	var keys []string
+	groups := map[string][]int{}
+	for i := 0; i < df.length; i++ {
+		key := df.at(i, index)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	var rowIndexes []int
+	for _, key := range keys {
+		group := groups[key]
+		n := fracCount(len(group), frac)
+		if n == 0 && frac > 0 {
+			n = 1
+		}
+		rowIndexes = append(rowIndexes, sampleIndexes(group, n, r)...)
+	}
+	sort.Ints(rowIndexes)
+
+	return df.selectRows(rowIndexes), nil
+}
+
+func allIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+func fracCount(total int, frac float64) int {
+	if frac <= 0 {
+		return 0
+	}
+	if frac >= 1 {
+		return total
+	}
+	return int(math.Round(float64(total) * frac))
+}
+
+// sampleIndexes 从indexes中不放回地随机抽取n个并升序返回
+func sampleIndexes(indexes []int, n int, r *rand.Rand) []int {
+	if n <= 0 {
+		return nil
+	}
+	if n > len(indexes) {
+		n = len(indexes)
+	}
+
+	sampled := make([]int, n)
+	for i, p := range r.Perm(len(indexes))[:n] {
+		sampled[i] = indexes[p]
+	}
+	sort.Ints(sampled)
+	return sampled
+}
@@ -0,0 +1,255 @@
+package pd
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NA FromSqlRows 中NULL对应的默认字符串
+const NA = "NA"
+
+// Dialect 数据库方言，决定占位符和标识符的写法
+type Dialect int
+
+const (
+	DialectMySQL Dialect = iota
+	DialectPostgres
+	DialectSQLite
+)
+
+// maxParams 单条语句中占位符的最大数量
+func (d Dialect) maxParams() int {
+	switch d {
+	case DialectPostgres, DialectMySQL:
+		return 65535
+	default:
+		// 旧版本sqlite的SQLITE_MAX_VARIABLE_NUMBER为999
+		return 999
+	}
+}
+
+func (d Dialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+func (d Dialect) quoteIdent(name string) string {
+	if d == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteTable 表名可以带schema或数据库前缀，例如 public.users，每一部分分别加引号
+func (d Dialect) quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = d.quoteIdent(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+type sqlOptions struct {
+	nullString string
+	insertNull string
+	dialect    Dialect
+	batchSize  int
+	columns    []string
+}
+
+// SqlOption FromSqlRows和InsertInto的可选参数
+type SqlOption func(*sqlOptions)
+
+// WithNullString 设置 FromSqlRows 中NULL对应的字符串，默认为 NA
+func WithNullString(nullString string) SqlOption {
+	return func(o *sqlOptions) {
+		o.nullString = nullString
+	}
+}
+
+// WithInsertNull 设置 InsertInto 时插入为NULL的字符串，默认只有空字符串插入为NULL，
+// 需要把 FromSqlRows 读取的 NA 写回为NULL时传入 NA，此时空字符串按空字符串插入
+func WithInsertNull(nullString string) SqlOption {
+	return func(o *sqlOptions) {
+		o.insertNull = nullString
+	}
+}
+
+// WithDialect 设置 InsertInto 使用的数据库方言，默认为 DialectMySQL
+func WithDialect(dialect Dialect) SqlOption {
+	return func(o *sqlOptions) {
+		o.dialect = dialect
+	}
+}
+
+// WithBatchSize 设置 InsertInto 每条INSERT语句的行数，默认为500，会按方言的占位符上限自动减小
+func WithBatchSize(batchSize int) SqlOption {
+	return func(o *sqlOptions) {
+		if batchSize > 0 {
+			o.batchSize = batchSize
+		}
+	}
+}
+
+// WithColumns 设置 InsertInto 插入的列，默认为所有列
+func WithColumns(columns ...string) SqlOption {
+	return func(o *sqlOptions) {
+		o.columns = columns
+	}
+}
+
+func newSqlOptions(opts []SqlOption) *sqlOptions {
+	o := &sqlOptions{nullString: NA, dialect: DialectMySQL, batchSize: 500}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// SqlExecer 可以执行sql语句的对象，*sql.DB、*sql.Tx、*sql.Conn都满足该接口
+type SqlExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// FromSqlRows 读取查询结果，列名作为表头，所有值都转为字符串，NULL转为 NA，读取完成后会关闭rows
+func FromSqlRows(rows *sql.Rows, opts ...SqlOption) (*DataFrame, error) {
+	o := newSqlOptions(opts)
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	df := NewDataFrame("")
+	df.SetHeads(columns)
+
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var records [][]string
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		record := make([]string, len(columns))
+		for i, v := range values {
+			record[i] = formatSqlValue(v, o.nullString)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	df.SetRows(records)
+	return df, nil
+}
+
+// formatSqlValue 将driver返回的值转为字符串，时间格式与 AutoFillSheet 一致
+func formatSqlValue(v any, nullString string) string {
+	switch v := v.(type) {
+	case nil:
+		return nullString
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// InsertInto 使用多行INSERT语句分批插入所有行，返回插入的行数，table可以带schema前缀，例如 public.users
+// 空字符串插入为NULL，可以通过 WithInsertNull 修改
+// 不会自动开启事务，需要全部成功或全部失败时传入 *sql.Tx
+func (df *DataFrame) InsertInto(db SqlExecer, table string, opts ...SqlOption) (int64, error) {
+	o := newSqlOptions(opts)
+
+	columns := o.columns
+	if len(columns) == 0 {
+		columns = df.heads
+	}
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("no columns to insert")
+	}
+	positions := make([]int, len(columns))
+	for i, column := range columns {
+		position, ok := df.headIndexMap[column]
+		if !ok {
+			return 0, fmt.Errorf("cannot find head %s", column)
+		}
+		positions[i] = position
+	}
+
+	batchSize := o.batchSize
+	if maxRows := o.dialect.maxParams() / len(columns); batchSize > maxRows {
+		batchSize = maxRows
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("too many columns for one insert statement: %d", len(columns))
+	}
+
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = o.dialect.quoteIdent(column)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ",
+		o.dialect.quoteTable(table), strings.Join(quotedColumns, ", "))
+
+	var inserted int64
+	for start := 0; start < df.length; start += batchSize {
+		end := start + batchSize
+		if end > df.length {
+			end = df.length
+		}
+
+		var sb strings.Builder
+		sb.WriteString(prefix)
+		args := make([]any, 0, (end-start)*len(columns))
+		for i := start; i < end; i++ {
+			if i > start {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("(")
+			for j, position := range positions {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(o.dialect.placeholder(len(args) + 1))
+
+				var value any
+				if cell := df.at(i, position); cell != o.insertNull {
+					value = cell
+				}
+				args = append(args, value)
+			}
+			sb.WriteString(")")
+		}
+
+		if _, err := db.Exec(sb.String(), args...); err != nil {
+			return inserted, fmt.Errorf("insert rows %d-%d: %w", start, end-1, err)
+		}
+		inserted += int64(end - start)
+	}
+
+	return inserted, nil
+}
@@ -0,0 +1,192 @@
+package pd
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDriver 记录执行的语句和参数，不连接真正的数据库
+type fakeDriver struct {
+	mu    sync.Mutex
+	execs []fakeExec
+}
+
+type fakeExec struct {
+	query string
+	args  []any
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.execs = append(c.driver.execs, fakeExec{query: query, args: values})
+	return driver.RowsAffected(len(args)), nil
+}
+
+var fakeDriverCount int
+
+// openFakeDB 每个测试注册一个新的driver，避免测试之间共享记录
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	t.Helper()
+	fakeDriverCount++
+	name := "pdfake" + strconv.Itoa(fakeDriverCount)
+	d := &fakeDriver{}
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db, d
+}
+
+func newSqlTestDataFrame(rows int) *DataFrame {
+	df := NewDataFrame("users")
+	df.SetHeads([]string{"id", "name", "note"})
+	for i := 0; i < rows; i++ {
+		df.AppendRecord([]string{strconv.Itoa(i), "user" + strconv.Itoa(i), ""})
+	}
+	return df
+}
+
+func TestInsertIntoPlaceholders(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		table   string
+		want    string
+	}{
+		{DialectMySQL, "users", "INSERT INTO `users` (`id`, `name`, `note`) VALUES (?, ?, ?), (?, ?, ?)"},
+		{DialectSQLite, "main.users", `INSERT INTO "main"."users" ("id", "name", "note") VALUES (?, ?, ?), (?, ?, ?)`},
+		{DialectPostgres, "public.users", `INSERT INTO "public"."users" ("id", "name", "note") VALUES ($1, $2, $3), ($4, $5, $6)`},
+		{DialectMySQL, "shop.users", "INSERT INTO `shop`.`users` (`id`, `name`, `note`) VALUES (?, ?, ?), (?, ?, ?)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			db, d := openFakeDB(t)
+			n, err := newSqlTestDataFrame(2).InsertInto(db, tt.table, WithDialect(tt.dialect))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 2 || len(d.execs) != 1 {
+				t.Fatalf("inserted %d rows in %d statements", n, len(d.execs))
+			}
+			if d.execs[0].query != tt.want {
+				t.Fatalf("query = %s\nwant    %s", d.execs[0].query, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertIntoBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      int
+		dialect   Dialect
+		batchSize int
+		want      []int
+	}{
+		{"batch size", 5, DialectMySQL, 2, []int{2, 2, 1}},
+		// sqlite最多999个占位符，3列时每条语句最多333行
+		{"sqlite max params", 700, DialectSQLite, 500, []int{333, 333, 34}},
+		{"postgres keeps batch size", 700, DialectPostgres, 500, []int{500, 200}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, d := openFakeDB(t)
+			n, err := newSqlTestDataFrame(tt.rows).InsertInto(db, "users",
+				WithDialect(tt.dialect), WithBatchSize(tt.batchSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(tt.rows) {
+				t.Fatalf("inserted %d rows, want %d", n, tt.rows)
+			}
+
+			var got []int
+			next := 0
+			for _, exec := range d.execs {
+				rows := len(exec.args) / 3
+				got = append(got, rows)
+				if first := exec.args[0]; first != strconv.Itoa(next) {
+					t.Fatalf("batch starts with id %v, want %d", first, next)
+				}
+				next += rows
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("batches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertIntoNull(t *testing.T) {
+	df := NewDataFrame("users")
+	df.SetHeads([]string{"id", "note"})
+	df.SetRows([][]string{{"1", ""}, {"2", NA}})
+
+	tests := []struct {
+		name string
+		opts []SqlOption
+		want []any
+	}{
+		{"empty cells are null", nil, []any{"1", nil, "2", NA}},
+		{"custom null string", []SqlOption{WithInsertNull(NA)}, []any{"1", "", "2", nil}},
+		{"null string only affects reading", []SqlOption{WithNullString("")}, []any{"1", nil, "2", NA}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, d := openFakeDB(t)
+			if _, err := df.InsertInto(db, "users", tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if got := d.execs[0].args; !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("args = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertIntoErrors(t *testing.T) {
+	db, _ := openFakeDB(t)
+	df := newSqlTestDataFrame(1)
+
+	if _, err := df.InsertInto(db, "users", WithColumns("missing")); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := NewDataFrame("").InsertInto(db, "users"); err == nil {
+		t.Fatal("expected error for no columns")
+	}
+}
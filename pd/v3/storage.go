@@ -0,0 +1,272 @@
+package pd
+
+// maxDictSize 字典中不重复的值超过该数量，且超过行数的一半时，列改为直接保存每行的值
+const maxDictSize = 1 << 16
+
+// column 列式存储的一列
+// 默认使用字典编码，相同的值只保存一次，codes为每行的值在dict中的编号，编号0固定为空字符串
+// 不重复的值太多时字典反而更占内存，此时改为plain模式，直接在values中保存每行的值
+type column struct {
+	dict   []string
+	lookup map[string]uint32
+	codes  []uint32
+
+	plain  bool
+	values []string
+}
+
+func newColumn(length int) *column {
+	return &column{
+		dict:   []string{""},
+		lookup: map[string]uint32{"": 0},
+		codes:  make([]uint32, length),
+	}
+}
+
+func (c *column) get(i int) string {
+	if c.plain {
+		return c.values[i]
+	}
+	return c.dict[c.codes[i]]
+}
+
+func (c *column) set(i int, value string) {
+	if c.plain {
+		c.values[i] = value
+		return
+	}
+	c.codes[i] = c.intern(value)
+	c.checkDict()
+}
+
+func (c *column) append(value string) {
+	if c.plain {
+		c.values = append(c.values, value)
+		return
+	}
+	c.codes = append(c.codes, c.intern(value))
+	c.checkDict()
+}
+
+// mapValues 用fn的返回值替换每个值，字典模式下每个不重复的值只调用一次fn
+func (c *column) mapValues(fn func(value string) string) {
+	if c.plain {
+		for i, value := range c.values {
+			c.values[i] = fn(value)
+		}
+		return
+	}
+
+	newCol := newColumn(len(c.codes))
+	remap := make([]uint32, len(c.dict))
+	for code, value := range c.dict {
+		remap[code] = newCol.intern(fn(value))
+	}
+	for i, code := range c.codes {
+		newCol.codes[i] = remap[code]
+	}
+	*c = *newCol
+	c.checkDict()
+}
+
+// resize 修改行数，新增的行为空字符串
+func (c *column) resize(length int) {
+	if c.plain {
+		c.values = resizeSlice(c.values, length)
+		return
+	}
+	c.codes = resizeSlice(c.codes, length)
+}
+
+func (c *column) intern(value string) uint32 {
+	code, ok := c.lookup[value]
+	if !ok {
+		code = uint32(len(c.dict))
+		c.dict = append(c.dict, value)
+		c.lookup[value] = code
+	}
+	return code
+}
+
+// checkDict 字典过大时改为plain模式
+func (c *column) checkDict() {
+	if len(c.dict) <= maxDictSize || len(c.dict)*2 <= len(c.codes) {
+		return
+	}
+	c.values = make([]string, len(c.codes))
+	for i, code := range c.codes {
+		c.values[i] = c.dict[code]
+	}
+	c.plain = true
+	c.dict, c.lookup, c.codes = nil, nil, nil
+}
+
+// pick 返回只包含指定行的新列
+func (c *column) pick(rowIndexes []int) *column {
+	if c.plain {
+		values := make([]string, len(rowIndexes))
+		for i, rowIndex := range rowIndexes {
+			values[i] = c.values[rowIndex]
+		}
+		return &column{plain: true, values: values}
+	}
+
+	// remap 原编号到新编号的映射，新字典只包含用到的值
+	newCol := newColumn(len(rowIndexes))
+	remap := make([]uint32, len(c.dict))
+	for i, rowIndex := range rowIndexes {
+		code := c.codes[rowIndex]
+		if code != 0 && remap[code] == 0 {
+			remap[code] = newCol.intern(c.dict[code])
+		}
+		newCol.codes[i] = remap[code]
+	}
+	newCol.checkDict()
+	return newCol
+}
+
+func resizeSlice[T any](s []T, length int) []T {
+	if length <= len(s) {
+		return s[:length]
+	}
+	if length <= cap(s) {
+		var zero T
+		oldLength := len(s)
+		s = s[:length]
+		for i := oldLength; i < length; i++ {
+			s[i] = zero
+		}
+		return s
+	}
+	newSlice := make([]T, length)
+	copy(newSlice, s)
+	return newSlice
+}
+
+// width 列数，不小于表头的数量
+func (df *DataFrame) width() int {
+	return len(df.columns)
+}
+
+// at 返回单元格的值，超出范围时返回空字符串
+func (df *DataFrame) at(rowIndex, position int) string {
+	if rowIndex < 0 || rowIndex >= df.length || position < 0 || position >= len(df.columns) {
+		return ""
+	}
+	return df.columns[position].get(rowIndex)
+}
+
+// recordWidth 输出的每行的长度，为表头数量和列数中较大的一个，没有表头的列也会输出
+func (df *DataFrame) recordWidth() int {
+	if len(df.heads) > len(df.columns) {
+		return len(df.heads)
+	}
+	return len(df.columns)
+}
+
+// outputHeads 输出时使用的表头，没有表头的列用空字符串补齐到 recordWidth
+func (df *DataFrame) outputHeads() []string {
+	heads := make([]string, df.recordWidth())
+	copy(heads, df.heads)
+	return heads
+}
+
+// record 返回一行的拷贝，长度为 recordWidth
+func (df *DataFrame) record(rowIndex int) []string {
+	record := make([]string, df.recordWidth())
+	for j := range record {
+		record[j] = df.at(rowIndex, j)
+	}
+	return record
+}
+
+// records 返回所有行的拷贝
+func (df *DataFrame) records() [][]string {
+	records := make([][]string, df.length)
+	for i := range records {
+		records[i] = df.record(i)
+	}
+	return records
+}
+
+// columnValues 返回一列的所有值，列不存在时全部为空字符串
+func (df *DataFrame) columnValues(position int) []string {
+	values := make([]string, df.length)
+	if position >= 0 && position < len(df.columns) {
+		col := df.columns[position]
+		for i := range values {
+			values[i] = col.get(i)
+		}
+	}
+	return values
+}
+
+// grow 保证至少有width列、length行
+func (df *DataFrame) grow(width, length int) {
+	if length > df.length {
+		for _, col := range df.columns {
+			col.resize(length)
+		}
+		df.length = length
+	}
+	for len(df.columns) < width {
+		df.columns = append(df.columns, newColumn(df.length))
+	}
+}
+
+// setCell 设置单元格的值，超出范围时自动扩展行和列
+func (df *DataFrame) setCell(rowIndex, position int, value string) {
+	df.grow(position+1, rowIndex+1)
+	df.columns[position].set(rowIndex, value)
+}
+
+// appendRecord 追加一行，record比列数短时补空字符串，比列数长时自动扩展列
+func (df *DataFrame) appendRecord(record []string) {
+	df.grow(len(record), 0)
+	for j, col := range df.columns {
+		var value string
+		if j < len(record) {
+			value = record[j]
+		}
+		col.append(value)
+	}
+	df.length++
+}
+
+// resetRows 用rows替换所有行，列数为表头的数量与最长的行中较大的一个，原有的列全部丢弃
+func (df *DataFrame) resetRows(rows [][]string) {
+	df.columns = nil
+	df.length = 0
+	width := len(df.heads)
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	df.grow(width, 0)
+	for _, row := range rows {
+		df.appendRecord(row)
+	}
+}
+
+// pickRows 返回只包含指定行的列，行可以重复
+func (df *DataFrame) pickRows(rowIndexes []int) []*column {
+	columns := make([]*column, len(df.columns))
+	for j, col := range df.columns {
+		columns[j] = col.pick(rowIndexes)
+	}
+	return columns
+}
+
+// pickColumns 返回只包含指定行和指定位置的列，位置超出列数时为空列
+func (df *DataFrame) pickColumns(rowIndexes []int, positions []int) []*column {
+	columns := make([]*column, len(positions))
+	for j, position := range positions {
+		if position < len(df.columns) {
+			columns[j] = df.columns[position].pick(rowIndexes)
+		} else {
+			columns[j] = newColumn(len(rowIndexes))
+		}
+	}
+	return columns
+}
@@ -0,0 +1,256 @@
+package pd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	benchRows    = 1000000
+	benchColumns = 30
+	// benchDistinct 每列不重复的值的数量
+	benchDistinct = 50
+)
+
+// benchValues 每列可能的值，生成每行时会复制一份，与从文件读取时每个单元格都是新的字符串一致
+func benchValues() [][]string {
+	values := make([][]string, benchColumns)
+	for j := range values {
+		values[j] = make([]string, benchDistinct)
+		for k := range values[j] {
+			values[j][k] = "col" + strconv.Itoa(j) + "_value" + strconv.Itoa(k)
+		}
+	}
+	return values
+}
+
+func benchHeads() []string {
+	heads := make([]string, benchColumns)
+	for j := range heads {
+		heads[j] = "col" + strconv.Itoa(j)
+	}
+	return heads
+}
+
+// buildBenchDataFrame 逐行追加生成 benchRows 行 benchColumns 列的df
+func buildBenchDataFrame(values [][]string) *DataFrame {
+	df := NewDataFrame("bench")
+	df.SetHeads(benchHeads())
+	record := make([]string, benchColumns)
+	for i := 0; i < benchRows; i++ {
+		for j := range record {
+			record[j] = string([]byte(values[j][(i+j)%benchDistinct]))
+		}
+		df.AppendRecord(record)
+	}
+	return df
+}
+
+// liveHeap GC后仍在使用的堆内存
+func liveHeap() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// reportRetained 报告build生成的对象在GC后占用的内存
+func reportRetained(b *testing.B, build func() any) {
+	b.ReportAllocs()
+	var retained uint64
+	for n := 0; n < b.N; n++ {
+		before := liveHeap()
+		data := build()
+		after := liveHeap()
+		runtime.KeepAlive(data)
+		if after > before {
+			retained += after - before
+		}
+	}
+	b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+}
+
+func BenchmarkColumnStore(b *testing.B) {
+	values := benchValues()
+	reportRetained(b, func() any {
+		return buildBenchDataFrame(values)
+	})
+}
+
+// BenchmarkRowStore 与按行保存 [][]string 的方式对比
+func BenchmarkRowStore(b *testing.B) {
+	values := benchValues()
+	reportRetained(b, func() any {
+		rows := make([][]string, benchRows)
+		for i := range rows {
+			row := make([]string, benchColumns)
+			for j := range row {
+				row[j] = string([]byte(values[j][(i+j)%benchDistinct]))
+			}
+			rows[i] = row
+		}
+		return rows
+	})
+}
+
+func BenchmarkSetRows(b *testing.B) {
+	values := benchValues()
+	rows := buildBenchDataFrame(values).GetRows()
+	b.ResetTimer()
+	reportRetained(b, func() any {
+		df := NewDataFrame("bench")
+		df.SetHeads(benchHeads())
+		df.SetRows(rows)
+		return df
+	})
+}
+
+func BenchmarkGetValue(b *testing.B) {
+	df := buildBenchDataFrame(benchValues())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = df.GetValue(n%benchRows, n%benchColumns)
+	}
+}
+
+func TestRowWidthKeepsHeadlessColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		build  func(df *DataFrame)
+		want   [][]string
+		csv    string
+		reused bool
+	}{
+		{
+			name: "rows wider than heads",
+			build: func(df *DataFrame) {
+				df.SetHeads([]string{"a", "b"})
+				df.SetRows([][]string{{"1", "2", "note"}})
+			},
+			want: [][]string{{"1", "2", "note"}},
+			csv:  "a,b,\n1,2,note\n",
+		},
+		{
+			name: "shorter SetHeads after SetRows",
+			build: func(df *DataFrame) {
+				df.SetRows([][]string{{"1", "2", "3"}})
+				df.SetHeads([]string{"a"})
+			},
+			want: [][]string{{"1", "2", "3"}},
+			csv:  "a,,\n1,2,3\n",
+		},
+		{
+			name: "SetValue beyond heads",
+			build: func(df *DataFrame) {
+				df.SetHeads([]string{"a"})
+				df.SetRows([][]string{{"1"}})
+				df.SetValue(0, 2, "x")
+			},
+			want: [][]string{{"1", "", "x"}},
+			csv:  "a,,\n1,,x\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df := NewDataFrame("width")
+			tt.build(df)
+			if got := df.GetRows(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("rows = %q, want %q", got, tt.want)
+			}
+
+			dst := filepath.Join(t.TempDir(), "out.csv")
+			if err := df.SaveCsv(dst); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.csv {
+				t.Fatalf("csv = %q, want %q", data, tt.csv)
+			}
+			if err := NewDataFrame("").ReadCsv(dst); err != nil {
+				t.Fatalf("saved csv cannot be read: %v", err)
+			}
+		})
+	}
+}
+
+func TestHeadlessColumnsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	df := NewDataFrame("data")
+	df.SetHeads([]string{"a", "b"})
+	df.SetRows([][]string{{"1", "2", "note"}})
+
+	src := filepath.Join(dir, "in.xlsx")
+	e := NewExcel()
+	e.AppendSheet(df)
+	if err := e.SaveExcelAllSheet(src); err != nil {
+		t.Fatal(err)
+	}
+	read := NewExcel()
+	if err := read.ReadExcelAllSheet(src); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(dir, "out.xlsx")
+	if err := read.SaveExcelAllSheet(dst); err != nil {
+		t.Fatal(err)
+	}
+	final := NewExcel()
+	if err := final.ReadExcelAllSheet(dst); err != nil {
+		t.Fatal(err)
+	}
+	if got := final.DataFramesMap["data"].GetRows(); !reflect.DeepEqual(got, [][]string{{"1", "2", "note"}}) {
+		t.Fatalf("rows = %q", got)
+	}
+
+	jsonDst := filepath.Join(dir, "out.json")
+	if err := df.SaveJson(jsonDst); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(jsonDst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"C": "note"`) {
+		t.Fatalf("json = %s", data)
+	}
+}
+
+func TestAutoFillSheetResetsWidth(t *testing.T) {
+	type narrow struct {
+		A string `pd:"a"`
+		B string `pd:"b"`
+	}
+	df := NewDataFrame("reuse")
+	df.SetHeads([]string{"a", "b", "c", "d", "e"})
+	df.SetRows([][]string{{"1", "2", "3", "4", "5"}})
+	if err := df.AutoFillSheet([]narrow{{"x", "y"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := df.GetRows(); !reflect.DeepEqual(got, [][]string{{"x", "y"}}) {
+		t.Fatalf("rows = %q", got)
+	}
+}
+
+func TestAppendRecordExtendsHeads(t *testing.T) {
+	df := NewDataFrame("append")
+	df.SetHeads([]string{"C", "b"})
+	df.AppendRecord([]string{"1", "2", "3", "4"})
+
+	if want := []string{"C", "b", "C_2", "D"}; !reflect.DeepEqual(df.GetHeads(), want) {
+		t.Fatalf("heads = %q, want %q", df.GetHeads(), want)
+	}
+	if got := df.GetValue(0, "C_2"); got != "3" {
+		t.Fatalf("C_2 = %q, want 3", got)
+	}
+	if got := df.GetRows(); !reflect.DeepEqual(got, [][]string{{"1", "2", "3", "4"}}) {
+		t.Fatalf("rows = %q", got)
+	}
+}
@@ -0,0 +1,225 @@
+package pd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Str 返回列的字符串操作，列不存在时在调用具体操作时返回错误
+func (df *DataFrame) Str(head string) *StrAccessor {
+	return &StrAccessor{df: df, head: head}
+}
+
+// transform 对列中的每个值调用fn并写回原列，没有索引时每个不重复的值只调用一次fn
+// 有索引时先计算出整列的新值，唯一索引的键重复时返回错误且不做修改
+func (s *StrAccessor) transform(fn func(value string) string) error {
+	position, ok := s.df.headIndexMap[s.head]
+	if !ok {
+		return fmt.Errorf("cannot find head %s", s.head)
+	}
+	if s.df.index == nil {
+		s.df.grow(position+1, 0)
+		s.df.columns[position].mapValues(fn)
+		return nil
+	}
+	values := s.df.columnValues(position)
+	for i, value := range values {
+		values[i] = fn(value)
+	}
+	return s.df.setColumns([]string{s.head}, [][]string{values})
+}
+
+// setColumns 把values写入heads对应的列，values[j]为第j列所有行的值，不存在的列追加到表头
+// 先检查唯一索引再写入，键重复时返回错误且不做任何修改
+func (df *DataFrame) setColumns(heads []string, values [][]string) error {
+	var newHeads []string
+	newPositions := map[string]int{}
+	positions := make([]int, len(heads))
+	for j, head := range heads {
+		if position, ok := df.headIndexMap[head]; ok {
+			positions[j] = position
+		} else if position, ok := newPositions[head]; ok {
+			positions[j] = position
+		} else {
+			positions[j] = len(df.heads) + len(newHeads)
+			newPositions[head] = positions[j]
+			newHeads = append(newHeads, head)
+		}
+	}
+	if err := df.checkUniqueColumns(positions, values); err != nil {
+		return err
+	}
+
+	if len(newHeads) > 0 {
+		df.heads = append(df.heads, newHeads...)
+		df.updateHeadIndexMap()
+	}
+	for j, position := range positions {
+		df.grow(position+1, 0)
+		col := df.columns[position]
+		for i, value := range values[j] {
+			col.set(i, value)
+		}
+	}
+	df.refreshIndex()
+	return nil
+}
+
+// Trim 去掉首尾的空白字符，包括全角空格
+func (s *StrAccessor) Trim() error {
+	return s.transform(strings.TrimSpace)
+}
+
+func (s *StrAccessor) Lower() error {
+	return s.transform(strings.ToLower)
+}
+
+func (s *StrAccessor) Upper() error {
+	return s.transform(strings.ToUpper)
+}
+
+// ToHalfWidth 全角字符转为半角，例如 "ＡＢＣ１２３，" 转为 "ABC123,"，全角空格转为普通空格
+func (s *StrAccessor) ToHalfWidth() error {
+	return s.transform(toHalfWidth)
+}
+
+func toHalfWidth(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			return r - 0xFEE0
+		default:
+			return r
+		}
+	}, value)
+}
+
+// Normalize 全角转半角，合并连续的空白并去掉首尾空白，适合清洗地址、姓名等手工录入的列
+func (s *StrAccessor) Normalize() error {
+	return s.transform(func(value string) string {
+		return strings.Join(strings.FieldsFunc(toHalfWidth(value), unicode.IsSpace), " ")
+	})
+}
+
+// Replace 使用正则替换，repl中可以用 $1、${name} 引用分组
+func (s *StrAccessor) Replace(pattern string, repl string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	return s.transform(func(value string) string {
+		return re.ReplaceAllString(value, repl)
+	})
+}
+
+// Extract 使用正则提取内容写入新列，不匹配的行为空
+// into为空时每个命名分组写入同名的列，例如 `(?P<province>.+?省)(?P<city>.+?市)`；
+// go的正则不支持中文分组名，需要中文列名时通过into按顺序指定每个分组对应的列名
+func (s *StrAccessor) Extract(pattern string, into ...string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	position, ok := s.df.headIndexMap[s.head]
+	if !ok {
+		return fmt.Errorf("cannot find head %s", s.head)
+	}
+
+	var groups []int
+	var heads []string
+	if len(into) > 0 {
+		if len(into) > re.NumSubexp() {
+			return fmt.Errorf("pattern %s has %d groups, less than %d", pattern, re.NumSubexp(), len(into))
+		}
+		for i, head := range into {
+			groups = append(groups, i+1)
+			heads = append(heads, head)
+		}
+	} else {
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				groups = append(groups, i)
+				heads = append(heads, name)
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return fmt.Errorf("pattern %s has no named group", pattern)
+	}
+
+	values := make([][]string, len(groups))
+	for j := range values {
+		values[j] = make([]string, s.df.length)
+	}
+	for i := 0; i < s.df.length; i++ {
+		match := re.FindStringSubmatch(s.df.at(i, position))
+		if match == nil {
+			continue
+		}
+		for j, group := range groups {
+			values[j][i] = match[group]
+		}
+	}
+	return s.df.setColumns(heads, values)
+}
+
+// Split 按sep拆分为多列，into为新列的列名，拆分出的部分多于列数时剩余部分保留在最后一列，少于列数时补空
+func (s *StrAccessor) Split(sep string, into []string) error {
+	if len(into) == 0 {
+		return fmt.Errorf("into must not be empty")
+	}
+	position, ok := s.df.headIndexMap[s.head]
+	if !ok {
+		return fmt.Errorf("cannot find head %s", s.head)
+	}
+
+	values := make([][]string, len(into))
+	for j := range values {
+		values[j] = make([]string, s.df.length)
+	}
+	for i := 0; i < s.df.length; i++ {
+		for j, part := range strings.SplitN(s.df.at(i, position), sep, len(into)) {
+			values[j][i] = part
+		}
+	}
+	return s.df.setColumns(into, values)
+}
+
+var combinePlaceholder = regexp.MustCompile(`\{(\d+)\}`)
+
+// Combine 按模板合并多列写入outHead列，模板中的 {0}、{1}... 对应heads中的列，模板为空时直接拼接
+// 例如 Combine("地址", []string{"省", "市", "区"}, "{0}{1}{2}")
+func (df *DataFrame) Combine(outHead string, heads []string, template string) error {
+	positions, err := df.headPositions(heads)
+	if err != nil {
+		return err
+	}
+	for _, match := range combinePlaceholder.FindAllStringSubmatch(template, -1) {
+		if n, _ := strconv.Atoi(match[1]); n >= len(heads) {
+			return fmt.Errorf("template placeholder %s out of range", match[0])
+		}
+	}
+
+	values := make([]string, df.length)
+	for i := range values {
+		if template == "" {
+			var sb strings.Builder
+			for _, position := range positions {
+				sb.WriteString(df.at(i, position))
+			}
+			values[i] = sb.String()
+			continue
+		}
+		values[i] = combinePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+			n, _ := strconv.Atoi(placeholder[1 : len(placeholder)-1])
+			return df.at(i, positions[n])
+		})
+	}
+
+	return df.setColumns([]string{outHead}, [][]string{values})
+}
@@ -0,0 +1,113 @@
+package pd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type structOptions struct {
+	sheetName string
+}
+
+// StructOption ReadStructs和WriteStructs的可选参数
+type StructOption func(*structOptions)
+
+// WithSheetName 指定读写xlsx时使用的sheet，读取时默认为第一个sheet，写入时默认为Sheet1
+func WithSheetName(sheetName string) StructOption {
+	return func(o *structOptions) {
+		o.sheetName = sheetName
+	}
+}
+
+func newStructOptions(opts []StructOption) *structOptions {
+	o := &structOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ReadStructs 读取文件并填充到结构体切片中，根据扩展名选择csv、xlsx或json格式，T可以是结构体或结构体指针
+func ReadStructs[T any](path string, opts ...StructOption) ([]T, error) {
+	o := newStructOptions(opts)
+
+	df, err := readDataFrame(path, o.sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []T
+	if err := df.AutoFillStruct(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// WriteStructs 将结构体切片写入文件，根据扩展名选择csv、xlsx或json格式，T可以是结构体或结构体指针
+func WriteStructs[T any](path string, items []T, opts ...StructOption) error {
+	o := newStructOptions(opts)
+
+	df := NewDataFrame(o.sheetName)
+	if err := df.AutoFillSheet(items); err != nil {
+		return err
+	}
+
+	return saveDataFrame(path, df)
+}
+
+func readDataFrame(path string, sheetName string) (*DataFrame, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		df := NewDataFrame(sheetName)
+		if err := df.ReadCsv(path); err != nil {
+			return nil, err
+		}
+		return df, nil
+
+	case ".json":
+		df := NewDataFrame(sheetName)
+		if err := df.ReadJson(path); err != nil {
+			return nil, err
+		}
+		return df, nil
+
+	case ".xlsx":
+		e := NewExcel()
+		if err := e.ReadExcelAllSheet(path); err != nil {
+			return nil, err
+		}
+		if sheetName == "" {
+			if len(e.SheetNames) == 0 {
+				return nil, fmt.Errorf("excel file %s has no sheet", path)
+			}
+			sheetName = e.SheetNames[0]
+		}
+		df, ok := e.DataFramesMap[sheetName]
+		if !ok {
+			return nil, fmt.Errorf("cannot find sheet %s", sheetName)
+		}
+		return df, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported file extension %q", ext)
+	}
+}
+
+func saveDataFrame(path string, df *DataFrame) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return df.SaveCsv(path)
+
+	case ".json":
+		return df.SaveJson(path)
+
+	case ".xlsx":
+		e := NewExcel()
+		e.AppendSheet(df)
+		return e.SaveExcelAllSheet(path)
+
+	default:
+		return fmt.Errorf("unsupported file extension %q", ext)
+	}
+}
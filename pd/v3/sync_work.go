@@ -0,0 +1,189 @@
+package pd
+
+// View 在读锁内执行fn，fn中不能修改df，也不能把df的切片带出fn
+func (sdf *SyncDataFrame) View(fn func(df *DataFrame) error) error {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return fn(sdf.df)
+}
+
+// Update 在写锁内对df的拷贝执行fn，fn返回nil时才会替换原df，返回错误时所有修改都会被丢弃
+func (sdf *SyncDataFrame) Update(fn func(df *DataFrame) error) error {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+
+	newDf := sdf.df.Copy()
+	if err := fn(newDf); err != nil {
+		return err
+	}
+	sdf.df = newDf
+	return nil
+}
+
+// Snapshot 返回当前df的深拷贝，可以用于 Excel.AppendSheet 等非线程安全的操作
+func (sdf *SyncDataFrame) Snapshot() *DataFrame {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.Copy()
+}
+
+func (sdf *SyncDataFrame) SetHeads(heads []string) {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	sdf.df.SetHeads(append([]string{}, heads...))
+}
+
+func (sdf *SyncDataFrame) GetHeads() []string {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return append([]string{}, sdf.df.GetHeads()...)
+}
+
+func (sdf *SyncDataFrame) SetRows(rows [][]string) {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	sdf.df.SetRows(rows)
+}
+
+func (sdf *SyncDataFrame) SetRowsE(rows [][]string) error {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	return sdf.df.SetRowsE(rows)
+}
+
+func (sdf *SyncDataFrame) GetRows() [][]string {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.GetRows()
+}
+
+func (sdf *SyncDataFrame) GetSheetName() string {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.GetSheetName()
+}
+
+func (sdf *SyncDataFrame) SetSheetName(sheetName string) {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	sdf.df.SetSheetName(sheetName)
+}
+
+func (sdf *SyncDataFrame) GetValue(rowIndex int, head any) string {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.GetValue(rowIndex, head)
+}
+
+func (sdf *SyncDataFrame) SetValue(rowIndex int, head any, value string) {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	sdf.df.SetValue(rowIndex, head, value)
+}
+
+func (sdf *SyncDataFrame) GetValueE(rowIndex int, head any) (string, error) {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.GetValueE(rowIndex, head)
+}
+
+func (sdf *SyncDataFrame) SetValueE(rowIndex int, head any, value string) error {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	return sdf.df.SetValueE(rowIndex, head, value)
+}
+
+func (sdf *SyncDataFrame) GetLength() int {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.GetLength()
+}
+
+func (sdf *SyncDataFrame) UniqueRows() {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	sdf.df.UniqueRows()
+}
+
+// AppendRow 并发安全地追加一行，行索引在锁内分配，可以直接供多个goroutine使用
+func (sdf *SyncDataFrame) AppendRow(row map[string]string) int {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	return sdf.df.AppendRow(row)
+}
+
+// AppendRecord 并发安全地按列的顺序追加一行
+func (sdf *SyncDataFrame) AppendRecord(record []string) int {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	return sdf.df.AppendRecord(record)
+}
+
+// AppendRowE 并发安全地追加一行，唯一索引的键重复时返回错误
+func (sdf *SyncDataFrame) AppendRowE(row map[string]string) (int, error) {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	return sdf.df.AppendRowE(row)
+}
+
+// AppendRecordE 并发安全地按列的顺序追加一行，唯一索引的键重复时返回错误
+func (sdf *SyncDataFrame) AppendRecordE(record []string) (int, error) {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	return sdf.df.AppendRecordE(record)
+}
+
+// appendRows 在一次加锁内追加多行
+func (sdf *SyncDataFrame) appendRows(rows []map[string]string) {
+	sdf.lock.Lock()
+	defer sdf.lock.Unlock()
+	for _, row := range rows {
+		sdf.df.AppendRow(row)
+	}
+}
+
+func (sdf *SyncDataFrame) AutoFillStruct(dest any) error {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.AutoFillStruct(dest)
+}
+
+// AutoFillSheet 与 DataFrame.AutoFillSheet 相同，填充失败时不会修改原有内容
+func (sdf *SyncDataFrame) AutoFillSheet(dest any) error {
+	return sdf.Update(func(df *DataFrame) error {
+		return df.AutoFillSheet(dest)
+	})
+}
+
+// ReadCsv 与 DataFrame.ReadCsv 相同，读取失败时不会修改原有内容
+func (sdf *SyncDataFrame) ReadCsv(src string) error {
+	return sdf.Update(func(df *DataFrame) error {
+		return df.ReadCsv(src)
+	})
+}
+
+func (sdf *SyncDataFrame) SaveCsv(dst string) error {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.SaveCsv(dst)
+}
+
+// ReadJson 与 DataFrame.ReadJson 相同，读取失败时不会修改原有内容
+func (sdf *SyncDataFrame) ReadJson(src string) error {
+	return sdf.Update(func(df *DataFrame) error {
+		return df.ReadJson(src)
+	})
+}
+
+func (sdf *SyncDataFrame) SaveJson(dst string) error {
+	sdf.lock.RLock()
+	defer sdf.lock.RUnlock()
+	return sdf.df.SaveJson(dst)
+}
+
+// AppendSyncSheet 将线程安全的df的快照追加到excel中
+func (e *Excel) AppendSyncSheet(sdfs ...*SyncDataFrame) {
+	for _, sdf := range sdfs {
+		e.AppendSheet(sdf.Snapshot())
+	}
+}
@@ -0,0 +1,98 @@
+package pd
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestSyncDataFrameConcurrent 并发读写，需要通过 go test -race 运行才能发现数据竞争
+func TestSyncDataFrameConcurrent(t *testing.T) {
+	const (
+		workers = 8
+		rounds  = 200
+	)
+
+	sdf := NewSyncDataFrame("sync")
+	sdf.SetHeads([]string{"id", "worker", "count"})
+	if err := sdf.Update(func(df *DataFrame) error {
+		return df.SetIndex("worker")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		worker := strconv.Itoa(w)
+		wg.Add(5)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				sdf.AppendRow(map[string]string{"id": strconv.Itoa(i), "worker": worker})
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				// 行只会增加，读到的长度之内的行一定存在
+				if length := sdf.GetLength(); length > 0 {
+					if err := sdf.SetValueE(i%length, "count", worker); err != nil {
+						t.Error(err)
+					}
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				if err := sdf.Update(func(df *DataFrame) error {
+					df.AppendRecord([]string{"update", worker})
+					return nil
+				}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				for _, row := range sdf.GetRows() {
+					if len(row) != 3 {
+						t.Errorf("row has %d cells, want 3", len(row))
+						return
+					}
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				_ = sdf.View(func(df *DataFrame) error {
+					df.LookupAll(worker)
+					df.Lookup(worker)
+					return nil
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := sdf.GetLength(), workers*rounds*2; got != want {
+		t.Fatalf("length = %d, want %d", got, want)
+	}
+	if err := sdf.View(func(df *DataFrame) error {
+		for w := 0; w < workers; w++ {
+			if got := len(df.LookupAll(strconv.Itoa(w))); got != rounds*2 {
+				t.Errorf("LookupAll(%d) returned %d rows, want %d", w, got, rounds*2)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,218 @@
+package pd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldTag 解析后的pd标签，格式为 `pd:"name|别名1|别名2,default=x,required,omitempty,order=1"`
+// 标签为空或为"-"时跳过该字段
+type fieldTag struct {
+	names        []string
+	defaultValue string
+	hasDefault   bool
+	required     bool
+	omitEmpty    bool
+	order        int
+}
+
+// parseFieldTag 解析字段的pd标签，返回false表示该字段需要跳过
+func parseFieldTag(field reflect.StructField) (fieldTag, bool, error) {
+	var tag fieldTag
+
+	raw := field.Tag.Get("pd")
+	if raw == "" || raw == "-" {
+		return tag, false, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	for _, name := range strings.Split(parts[0], "|") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			tag.names = append(tag.names, name)
+		}
+	}
+	if len(tag.names) == 0 {
+		return tag, false, fmt.Errorf("field %s has no column name in tag %q", field.Name, raw)
+	}
+
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "default":
+			tag.defaultValue = value
+			tag.hasDefault = true
+		case "required":
+			tag.required = true
+		case "omitempty":
+			tag.omitEmpty = true
+		case "order":
+			order, err := strconv.Atoi(value)
+			if err != nil {
+				return tag, false, fmt.Errorf("field %s has invalid order %q", field.Name, value)
+			}
+			tag.order = order
+		case "":
+		default:
+			return tag, false, fmt.Errorf("field %s has unknown tag option %q", field.Name, key)
+		}
+	}
+
+	return tag, true, nil
+}
+
+// columnNames 返回带前缀的列名及其所有别名，第一个为主列名
+func (t fieldTag) columnNames(prefix string) []string {
+	if prefix == "" {
+		return t.names
+	}
+	names := make([]string, len(t.names))
+	for i, name := range t.names {
+		names[i] = prefix + "_" + name
+	}
+	return names
+}
+
+// columnAliases 返回所有前缀与所有别名组合出的列名，读取时按顺序查找，第一个为主列名
+// 嵌套结构体的每个别名都可以作为前缀，例如 addr|地址 中的 city|城市 可以匹配 addr_city、addr_城市、地址_city、地址_城市
+func (t fieldTag) columnAliases(prefixes []string) []string {
+	if len(prefixes) == 0 {
+		return t.names
+	}
+	var names []string
+	for _, prefix := range prefixes {
+		names = append(names, t.columnNames(prefix)...)
+	}
+	return names
+}
+
+// isNestedStruct 判断字段是否需要作为嵌套结构体递归处理
+func isNestedStruct(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != reflect.TypeOf(time.Time{})
+	case reflect.Ptr:
+		return t.Elem().Kind() == reflect.Struct
+	default:
+		return false
+	}
+}
+
+type columnSpec struct {
+	name  string
+	order int
+}
+
+// structHeads 根据结构体类型生成表头，按order升序排列，order相同时保持字段顺序
+func structHeads(t reflect.Type) ([]string, error) {
+	var specs []columnSpec
+	if err := collectColumnSpecs(t, "", &specs); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		return specs[i].order < specs[j].order
+	})
+
+	heads := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		heads = append(heads, spec.name)
+	}
+	return heads, nil
+}
+
+func collectColumnSpecs(t reflect.Type, prefix string, specs *[]columnSpec) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if err := collectColumnSpecs(fieldType, prefix, specs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag, ok, err := parseFieldTag(field)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		columnName := tag.columnNames(prefix)[0]
+		if isNestedStruct(field.Type) {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if err := collectColumnSpecs(fieldType, columnName, specs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		*specs = append(*specs, columnSpec{name: columnName, order: tag.order})
+	}
+	return nil
+}
+
+// setFieldValue 将字符串解析后写入字段，解析失败时写入零值
+func setFieldValue(fieldVal reflect.Value, value string) error {
+	switch fieldVal.Kind() {
+	case reflect.String:
+		fieldVal.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intVal, _ := strconv.ParseInt(value, 10, 64)
+		fieldVal.SetInt(intVal)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintVal, _ := strconv.ParseUint(value, 10, 64)
+		fieldVal.SetUint(uintVal)
+	case reflect.Float32, reflect.Float64:
+		floatVal, _ := strconv.ParseFloat(value, 64)
+		fieldVal.SetFloat(floatVal)
+	case reflect.Bool:
+		boolVal, _ := strconv.ParseBool(value)
+		fieldVal.SetBool(boolVal)
+	case reflect.Struct:
+		if fieldVal.Type() != reflect.TypeOf(time.Time{}) {
+			return fmt.Errorf("unsupported field type: %v", fieldVal.Type())
+		}
+		timeVal, _ := time.Parse(time.RFC3339, value)
+		fieldVal.Set(reflect.ValueOf(timeVal))
+	default:
+		return fmt.Errorf("unsupported field type: %v", fieldVal.Kind())
+	}
+	return nil
+}
+
+// formatFieldValue 将字段格式化为字符串
+func formatFieldValue(fieldVal reflect.Value) (string, error) {
+	switch fieldVal.Kind() {
+	case reflect.String:
+		return fieldVal.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fieldVal.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fieldVal.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fieldVal.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fieldVal.Bool()), nil
+	case reflect.Struct:
+		if fieldVal.Type() != reflect.TypeOf(time.Time{}) {
+			return "", fmt.Errorf("unsupported field type: %v", fieldVal.Type())
+		}
+		return fieldVal.Interface().(time.Time).Format(time.RFC3339), nil
+	default:
+		return "", fmt.Errorf("unsupported field type: %v", fieldVal.Kind())
+	}
+}
@@ -0,0 +1,293 @@
+package pd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/wuyyyyyou/go-share/ioutils"
+)
+
+// cellRefPattern 公式中的单元格引用，可以带sheet前缀，例如 B5、$B$5、B5:B10、'报表 1'!B5
+var cellRefPattern = regexp.MustCompile(`((?:'(?:[^']|'')+'|[A-Za-z0-9_.\p{Han}]+)!)?(\$?[A-Z]{1,3})(\$?)(\d+)(?::(\$?[A-Z]{1,3})(\$?)(\d+))?`)
+
+// FillTemplate 打开模板文件，将每个df写入对应sheet的锚点处并另存为dst，模板中的logo、样式和公式都会保留
+// 数据超过一行时，在锚点行下方插入行，公式中的引用会随之调整，以锚点行结尾的范围会扩展到所有数据行，例如 SUM(B5:B5)
+// 锚点行每个单元格的样式和行高会复制到新插入的行，可以转为数字的值按数字写入，使模板中的公式能够计算
+// 加密的模板需要通过 WithReadPassword 传入密码，生成的文件使用相同的密码加密
+func FillTemplate(templatePath, dst string, anchors map[string]Anchor, opts ...ReadOption) error {
+	file, err := openExcelFile(templatePath, opts)
+	if err != nil {
+		return err
+	}
+	defer ioutils.CloseQuietly(file)
+
+	sheetNames := make([]string, 0, len(anchors))
+	for sheetName := range anchors {
+		sheetNames = append(sheetNames, sheetName)
+	}
+	sort.Strings(sheetNames)
+
+	for _, sheetName := range sheetNames {
+		if err := fillAnchor(file, sheetName, anchors[sheetName]); err != nil {
+			return fmt.Errorf("sheet %s: %w", sheetName, err)
+		}
+	}
+
+	// 清除公式的缓存值，打开文件时重新计算
+	if err := file.UpdateLinkedValue(); err != nil {
+		return err
+	}
+	return file.SaveAs(dst)
+}
+
+func fillAnchor(file *excelize.File, sheetName string, anchor Anchor) error {
+	if !lo.Contains(file.GetSheetList(), sheetName) {
+		return fmt.Errorf("cannot find sheet %s", sheetName)
+	}
+	if anchor.DF == nil {
+		return fmt.Errorf("dataframe is nil")
+	}
+	df := anchor.DF
+
+	col, row, err := excelize.CellNameToCoordinates(anchor.Cell)
+	if err != nil {
+		return err
+	}
+	if anchor.Heads {
+		if err := setTemplateRow(file, sheetName, col, row, df.heads); err != nil {
+			return err
+		}
+		row++
+	}
+	if lastRow := row + df.length - 1; lastRow > excelize.TotalRows {
+		return fmt.Errorf("sheet %s would have %d rows, exceeds the limit of %d", sheetName, lastRow, excelize.TotalRows)
+	}
+
+	if df.length > 1 {
+		if err := insertTemplateRows(file, sheetName, row, df.length-1); err != nil {
+			return err
+		}
+		if err := copyRowStyle(file, sheetName, col, row, len(df.heads), df.length-1); err != nil {
+			return err
+		}
+	}
+
+	positions := allIndexes(len(df.heads))
+	for i := 0; i < df.length; i++ {
+		if err := setTemplateRow(file, sheetName, col, row+i, df.pickRecord(i, positions)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTemplateRow 从(col, row)开始向右写入一行，可以转为数字的值按数字写入
+func setTemplateRow(file *excelize.File, sheetName string, col, row int, record []string) error {
+	cell, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	values := make([]any, len(record))
+	for i, value := range record {
+		values[i] = templateValue(value)
+	}
+	return file.SetSheetRow(sheetName, cell, &values)
+}
+
+// templateValue 转换后再格式化与原值相同的数字按数字写入，保留 007、0.10、超过15位的编号等原样的文本
+func templateValue(value string) any {
+	if f, err := strconv.ParseFloat(value, 64); err == nil && formatNumber(f) == value {
+		return f
+	}
+	return value
+}
+
+// copyRowStyle 将row行从col开始width个单元格的样式和行高复制到下面的n行
+func copyRowStyle(file *excelize.File, sheetName string, col, row, width, n int) error {
+	for j := 0; j < width; j++ {
+		from, err := excelize.CoordinatesToCellName(col+j, row)
+		if err != nil {
+			return err
+		}
+		styleID, err := file.GetCellStyle(sheetName, from)
+		if err != nil {
+			return err
+		}
+		top, _ := excelize.CoordinatesToCellName(col+j, row+1)
+		bottom, _ := excelize.CoordinatesToCellName(col+j, row+n)
+		if err := file.SetCellStyle(sheetName, top, bottom, styleID); err != nil {
+			return err
+		}
+	}
+
+	height, err := file.GetRowHeight(sheetName, row)
+	if err != nil {
+		return err
+	}
+	for i := 1; i <= n; i++ {
+		if err := file.SetRowHeight(sheetName, row+i, height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertTemplateRows 在row行下方插入n行，并调整所有sheet中引用了插入位置之后单元格的公式
+func insertTemplateRows(file *excelize.File, sheetName string, row, n int) error {
+	// 插入前记录所有公式，共享公式的子单元格此时才能得到正确的公式
+	type formulaCell struct {
+		col, row int
+		formula  string
+	}
+	formulas := map[string][]formulaCell{}
+	for _, name := range file.GetSheetList() {
+		maxCol, maxRow, err := sheetBounds(file, name)
+		if err != nil {
+			return err
+		}
+		for r := 1; r <= maxRow; r++ {
+			for c := 1; c <= maxCol; c++ {
+				cell, _ := excelize.CoordinatesToCellName(c, r)
+				formula, err := file.GetCellFormula(name, cell)
+				if err != nil {
+					return err
+				}
+				if formula != "" {
+					formulas[name] = append(formulas[name], formulaCell{col: c, row: r, formula: formula})
+				}
+			}
+		}
+	}
+
+	if err := file.InsertRows(sheetName, row+1, n); err != nil {
+		return err
+	}
+
+	for _, name := range file.GetSheetList() {
+		cells := formulas[name]
+		changed := false
+		adjusted := make([]string, len(cells))
+		for i, c := range cells {
+			adjusted[i] = shiftFormulaRows(c.formula, name, sheetName, row, n)
+			changed = changed || adjusted[i] != c.formula
+		}
+		if !changed {
+			continue
+		}
+
+		// 同一sheet中的公式全部重写为普通公式，避免共享公式只有部分单元格被修改
+		for i, c := range cells {
+			r := c.row
+			if name == sheetName && r > row {
+				r += n
+			}
+			cell, _ := excelize.CoordinatesToCellName(c.col, r)
+			if err := file.SetCellFormula(name, cell, ""); err != nil {
+				return err
+			}
+			if err := file.SetCellFormula(name, cell, adjusted[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sheetBounds 返回sheet已使用的最大列数和行数
+func sheetBounds(file *excelize.File, sheetName string) (int, int, error) {
+	maxCol, maxRow := 0, 0
+	dimension, err := file.GetSheetDimension(sheetName)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, last, _ := strings.Cut(dimension, ":"); last != "" {
+		if c, r, err := excelize.CellNameToCoordinates(last); err == nil {
+			maxCol, maxRow = c, r
+		}
+	}
+
+	rows, err := file.GetRows(sheetName)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(rows) > maxRow {
+		maxRow = len(rows)
+	}
+	for _, r := range rows {
+		if len(r) > maxCol {
+			maxCol = len(r)
+		}
+	}
+	return maxCol, maxRow, nil
+}
+
+// shiftFormulaRows 调整formulaSheet中的公式，使其适应在targetSheet的row行下方插入n行
+// 行号大于row的引用下移n行，结束行不小于row的范围扩展n行，字符串常量中的内容不会被修改
+func shiftFormulaRows(formula, formulaSheet, targetSheet string, row, n int) string {
+	var sb strings.Builder
+	for i, part := range strings.Split(formula, `"`) {
+		if i > 0 {
+			sb.WriteString(`"`)
+		}
+		// 奇数部分在引号内
+		if i%2 == 1 {
+			sb.WriteString(part)
+			continue
+		}
+		sb.WriteString(shiftRefs(part, formulaSheet, targetSheet, row, n))
+	}
+	return sb.String()
+}
+
+func shiftRefs(text, formulaSheet, targetSheet string, row, n int) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range cellRefPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[0], m[1]
+		// 前面是字母数字时为名称的一部分，后面是字母数字或括号时为函数名，例如 LOG10(
+		if start > 0 && isNameChar(text[start-1]) {
+			continue
+		}
+		if end < len(text) && (isNameChar(text[end]) || text[end] == '(') {
+			continue
+		}
+
+		sheet := formulaSheet
+		if m[2] >= 0 {
+			sheet = strings.TrimSuffix(text[m[2]:m[3]], "!")
+			if strings.HasPrefix(sheet, "'") {
+				sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+			}
+		}
+		if !strings.EqualFold(sheet, targetSheet) {
+			continue
+		}
+
+		sb.WriteString(text[last:m[4]])
+		startRow, _ := strconv.Atoi(text[m[8]:m[9]])
+		if startRow > row {
+			startRow += n
+		}
+		sb.WriteString(text[m[4]:m[8]] + strconv.Itoa(startRow))
+		if m[10] >= 0 {
+			endRow, _ := strconv.Atoi(text[m[14]:m[15]])
+			if endRow >= row {
+				endRow += n
+			}
+			sb.WriteString(":" + text[m[10]:m[14]] + strconv.Itoa(endRow))
+		}
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '.' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,57 @@
+package pd
+
+import "testing"
+
+func TestShiftFormulaRows(t *testing.T) {
+	// 锚点在第5行，插入3行
+	tests := []struct {
+		name         string
+		formula      string
+		formulaSheet string
+		want         string
+	}{
+		{"below anchor", "=B6*2", "data", "=B9*2"},
+		{"anchor row", "=B5", "data", "=B5"},
+		{"above anchor", "=B4+C1", "data", "=B4+C1"},
+		{"range ending on anchor", "=SUM(B2:B5)", "data", "=SUM(B2:B8)"},
+		{"single row range", "=SUM(B5:B5)", "data", "=SUM(B5:B8)"},
+		{"range below anchor", "=SUM(B6:B10)", "data", "=SUM(B9:B13)"},
+		{"range above anchor", "=SUM(B1:B4)", "data", "=SUM(B1:B4)"},
+		{"absolute refs", "=$B$6+B$5+$C7", "data", "=$B$9+B$5+$C10"},
+		{"absolute range", "=SUM($B$2:$B$5)", "data", "=SUM($B$2:$B$8)"},
+		{"same sheet prefix", "=data!B6", "data", "=data!B9"},
+		{"cross sheet ref", "=data!B6+SUM(data!B2:B5)", "summary", "=data!B9+SUM(data!B2:B8)"},
+		{"sheet prefix ignores case", "=DATA!B6", "summary", "=DATA!B9"},
+		{"other sheet", "=other!B6", "data", "=other!B6"},
+		{"unprefixed ref on other sheet", "=B6", "summary", "=B6"},
+		{"quoted sheet name", "='data'!B6", "summary", "='data'!B9"},
+		{"string literals", `=IF(A6="B6","B6:B10",B6)`, "data", `=IF(A9="B6","B6:B10",B9)`},
+		{"function name", "=LOG10(B6)+ATAN2(B6,1)", "data", "=LOG10(B9)+ATAN2(B9,1)"},
+		{"defined name", "=Rate_B6*B6", "data", "=Rate_B6*B9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shiftFormulaRows(tt.formula, tt.formulaSheet, "data", 5, 3); got != tt.want {
+				t.Fatalf("shiftFormulaRows(%q) = %q, want %q", tt.formula, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShiftFormulaRowsQuotedSheetNames(t *testing.T) {
+	tests := []struct {
+		formula     string
+		targetSheet string
+		want        string
+	}{
+		{"='报表 1'!B6", "报表 1", "='报表 1'!B9"},
+		{"=SUM('Bob''s data'!B2:B5)", "Bob's data", "=SUM('Bob''s data'!B2:B8)"},
+		{"=报表!B6", "报表", "=报表!B9"},
+		{"='报表 2'!B6", "报表 1", "='报表 2'!B6"},
+	}
+	for _, tt := range tests {
+		if got := shiftFormulaRows(tt.formula, "summary", tt.targetSheet, 5, 3); got != tt.want {
+			t.Errorf("shiftFormulaRows(%q) = %q, want %q", tt.formula, got, tt.want)
+		}
+	}
+}
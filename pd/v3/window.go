@@ -0,0 +1,258 @@
+package pd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GroupBy 按列分组，之后的累计和滑动窗口计算都在组内进行
+func (df *DataFrame) GroupBy(heads ...string) (*GroupBy, error) {
+	positions, err := df.headPositions(heads)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups [][]int
+	groupIndexes := map[string]int{}
+	for i := 0; i < df.length; i++ {
+		values := make([]string, len(positions))
+		for j, position := range positions {
+			values[j] = df.at(i, position)
+		}
+		key := strings.Join(values, "\x1F")
+
+		g, ok := groupIndexes[key]
+		if !ok {
+			g = len(groups)
+			groupIndexes[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+
+	return &GroupBy{df: df, heads: heads, groups: groups}, nil
+}
+
+// allRows 不分组时整个df为一组
+func (df *DataFrame) allRows() [][]int {
+	return [][]int{allIndexes(df.length)}
+}
+
+// groupValues 返回组内每一行在position列的值，position为-1时全部为空
+func (df *DataFrame) groupValues(group []int, position int) []string {
+	values := make([]string, len(group))
+	if position < 0 {
+		return values
+	}
+	for i, rowIndex := range group {
+		values[i] = df.at(rowIndex, position)
+	}
+	return values
+}
+
+// applyWindow 对每个组计算新值并写入outHead列，head为空时不读取任何列
+func (df *DataFrame) applyWindow(groups [][]int, head string, outHead string, fn func(values []string) []string) error {
+	position := -1
+	if head != "" {
+		var ok bool
+		if position, ok = df.headIndexMap[head]; !ok {
+			return fmt.Errorf("cannot find head %s", head)
+		}
+	}
+
+	results := make([][]string, len(groups))
+	for i, group := range groups {
+		results[i] = fn(df.groupValues(group, position))
+	}
+	for i, group := range groups {
+		for j, rowIndex := range group {
+			if err := df.SetValueE(rowIndex, outHead, results[i][j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseNumber(value string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return f, err == nil
+}
+
+func cumSum(values []string) []string {
+	results := make([]string, len(values))
+	sum := 0.0
+	for i, value := range values {
+		if f, ok := parseNumber(value); ok {
+			sum += f
+		}
+		results[i] = formatNumber(sum)
+	}
+	return results
+}
+
+func cumCount(values []string) []string {
+	results := make([]string, len(values))
+	for i := range values {
+		results[i] = strconv.Itoa(i + 1)
+	}
+	return results
+}
+
+// rank 相同的值排名相同，下一个排名跳过并列的数量，例如 1、2、2、4；空值不参与排名
+func rank(desc bool) func(values []string) []string {
+	return func(values []string) []string {
+		var order []int
+		for i, value := range values {
+			if value != "" {
+				order = append(order, i)
+			}
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			cmp := compareValues(values[order[a]], values[order[b]])
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+
+		results := make([]string, len(values))
+		for i, index := range order {
+			r := i + 1
+			if i > 0 && compareValues(values[index], values[order[i-1]]) == 0 {
+				r, _ = strconv.Atoi(results[order[i-1]])
+			}
+			results[index] = strconv.Itoa(r)
+		}
+		return results
+	}
+}
+
+func shift(n int) func(values []string) []string {
+	return func(values []string) []string {
+		results := make([]string, len(values))
+		for i := range values {
+			if j := i - n; j >= 0 && j < len(values) {
+				results[i] = values[j]
+			}
+		}
+		return results
+	}
+}
+
+func diff(periods int) func(values []string) []string {
+	return func(values []string) []string {
+		results := make([]string, len(values))
+		for i, value := range values {
+			j := i - periods
+			if j < 0 || j >= len(values) {
+				continue
+			}
+			current, ok1 := parseNumber(value)
+			previous, ok2 := parseNumber(values[j])
+			if ok1 && ok2 {
+				results[i] = formatNumber(current - previous)
+			}
+		}
+		return results
+	}
+}
+
+// CumSum 累计求和写入outHead列，非数字的值按0计算
+func (df *DataFrame) CumSum(head, outHead string) error {
+	return df.applyWindow(df.allRows(), head, outHead, cumSum)
+}
+
+// CumCount 从1开始的累计计数写入outHead列
+func (df *DataFrame) CumCount(outHead string) error {
+	return df.applyWindow(df.allRows(), "", outHead, cumCount)
+}
+
+// Rank 排名写入outHead列，比较规则与 OrderBy 相同，相同的值排名相同
+func (df *DataFrame) Rank(head, outHead string, desc bool) error {
+	return df.applyWindow(df.allRows(), head, outHead, rank(desc))
+}
+
+// Shift 将值向下移动n行写入outHead列，n为负数时向上移动，移出的位置为空
+func (df *DataFrame) Shift(head, outHead string, n int) error {
+	return df.applyWindow(df.allRows(), head, outHead, shift(n))
+}
+
+// Diff 当前行与前periods行的差写入outHead列，任一值不是数字时为空
+func (df *DataFrame) Diff(head, outHead string, periods int) error {
+	return df.applyWindow(df.allRows(), head, outHead, diff(periods))
+}
+
+// Rolling 创建滑动窗口，默认窗口内的行数不足window时结果为空
+func (df *DataFrame) Rolling(window int) *Rolling {
+	return &Rolling{df: df, groups: df.allRows(), window: window, minPeriods: window}
+}
+
+func (g *GroupBy) CumSum(head, outHead string) error {
+	return g.df.applyWindow(g.groups, head, outHead, cumSum)
+}
+
+func (g *GroupBy) CumCount(outHead string) error {
+	return g.df.applyWindow(g.groups, "", outHead, cumCount)
+}
+
+func (g *GroupBy) Rank(head, outHead string, desc bool) error {
+	return g.df.applyWindow(g.groups, head, outHead, rank(desc))
+}
+
+func (g *GroupBy) Shift(head, outHead string, n int) error {
+	return g.df.applyWindow(g.groups, head, outHead, shift(n))
+}
+
+func (g *GroupBy) Diff(head, outHead string, periods int) error {
+	return g.df.applyWindow(g.groups, head, outHead, diff(periods))
+}
+
+func (g *GroupBy) Rolling(window int) *Rolling {
+	return &Rolling{df: g.df, groups: g.groups, window: window, minPeriods: window}
+}
+
+// MinPeriods 设置窗口内至少需要多少个数字才输出结果
+func (r *Rolling) MinPeriods(n int) *Rolling {
+	r.minPeriods = n
+	return r
+}
+
+// apply 对每个窗口内的数字调用agg
+func (r *Rolling) apply(head, outHead string, agg AggFunc) error {
+	if r.window < 1 {
+		return fmt.Errorf("rolling window must be positive, got %d", r.window)
+	}
+	return r.df.applyWindow(r.groups, head, outHead, func(values []string) []string {
+		results := make([]string, len(values))
+		for i := range values {
+			start := i - r.window + 1
+			if start < 0 {
+				start = 0
+			}
+			window := values[start : i+1]
+			if len(parseNumbers(window)) >= r.minPeriods {
+				results[i] = agg(window)
+			}
+		}
+		return results
+	})
+}
+
+func (r *Rolling) Sum(head, outHead string) error {
+	return r.apply(head, outHead, AggSum)
+}
+
+func (r *Rolling) Mean(head, outHead string) error {
+	return r.apply(head, outHead, AggMean)
+}
+
+func (r *Rolling) Min(head, outHead string) error {
+	return r.apply(head, outHead, AggMin)
+}
+
+func (r *Rolling) Max(head, outHead string) error {
+	return r.apply(head, outHead, AggMax)
+}
@@ -1,19 +1,20 @@
 package pd
 
 import (
+	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/samber/lo"
 	"github.com/xuri/excelize/v2"
 
 	"github.com/wuyyyyyou/go-share/ioutils"
-	"github.com/wuyyyyyou/go-share/share"
 )
 
 func (df *DataFrame) updateHeadIndexMap() {
@@ -26,18 +27,49 @@ func (df *DataFrame) updateHeadIndexMap() {
 func (df *DataFrame) SetHeads(heads []string) {
 	df.heads = heads
 	df.updateHeadIndexMap()
+	df.grow(len(heads), 0)
+	df.refreshIndex()
 }
 
 func (df *DataFrame) GetHeads() []string {
 	return df.heads
 }
 
+// SetRows 替换所有行，行中的值会被复制到列式存储中，之后修改rows不会影响df
 func (df *DataFrame) SetRows(rows [][]string) {
-	df.rows = rows
+	df.resetRows(rows)
+	df.refreshIndex()
 }
 
+// SetRowsE 与 SetRows 相同，设置了唯一索引时rows中的键重复会返回错误且不做修改
+func (df *DataFrame) SetRowsE(rows [][]string) error {
+	if err := df.checkUniqueRows(rows); err != nil {
+		return err
+	}
+	df.SetRows(rows)
+	return nil
+}
+
+// GetRows 返回所有行的拷贝，每行的长度为表头数量和列数中较大的一个
+// 注意：数据按列存储后，每次调用都会重新生成所有行，修改返回值不会影响df
+// 原来通过 df.GetRows()[i][j] = v 修改的代码需要改为 df.SetValue(i, j, v)，只需要行数时使用 GetLength
 func (df *DataFrame) GetRows() [][]string {
-	return df.rows
+	return df.records()
+}
+
+func (df *DataFrame) GetSheetName() string {
+	return df.sheetName
+}
+
+func (df *DataFrame) SetSheetName(sheetName string) {
+	df.sheetName = sheetName
+}
+
+// Copy 深拷贝，返回的df与原df不共享任何切片，索引会在新df上重建
+func (df *DataFrame) Copy() *DataFrame {
+	newDf := df.selectRows(allIndexes(df.length))
+	df.copyIndex(newDf)
+	return newDf
 }
 
 func (df *DataFrame) GetValue(rowIndex int, head any) string {
@@ -57,22 +89,10 @@ func (df *DataFrame) GetValueE(rowIndex int, head any) (string, error) {
 		if !ok {
 			return "", fmt.Errorf("cannot find head %s", head)
 		}
-		if rowIndex >= len(df.rows) {
-			return "", fmt.Errorf("row index %d out of range", rowIndex)
-		}
-		if index >= len(df.rows[rowIndex]) {
-			return "", fmt.Errorf("head index %d out of range", index)
-		}
-		return df.rows[rowIndex][index], nil
+		return df.getValue(rowIndex, index)
 
 	case int:
-		if rowIndex >= len(df.rows) {
-			return "", fmt.Errorf("row index %d out of range", rowIndex)
-		}
-		if head >= len(df.rows[rowIndex]) {
-			return "", fmt.Errorf("head index %d out of range", head)
-		}
-		return df.rows[rowIndex][head], nil
+		return df.getValue(rowIndex, head)
 
 	default:
 		return "", fmt.Errorf("head type %T not supported", head)
@@ -81,34 +101,126 @@ func (df *DataFrame) GetValueE(rowIndex int, head any) (string, error) {
 
 // SetValueE 设置索引处的值，如果索引超出范围，会创建一个新的足够长的切片，接受head的类型为string或int
 // 如果head为string类型，且不存在，则会自动添加到heads中
+// 设置了唯一索引时，修改后的键与其他行重复会返回错误且不做修改
 func (df *DataFrame) SetValueE(rowIndex int, head any, value string) error {
+	var index int
 	switch head := head.(type) {
 	case string:
-		index, ok := df.headIndexMap[head]
+		var ok bool
+		index, ok = df.headIndexMap[head]
 		if !ok {
 			df.heads = append(df.heads, head)
 			df.updateHeadIndexMap()
+			df.refreshIndex()
 			index = df.headIndexMap[head]
 		}
-		if rowIndex >= len(df.rows) {
-			df.rows = share.SetSliceValue(df.rows, rowIndex, make([]string, len(df.heads)))
-		}
-		df.rows[rowIndex] = share.SetSliceValue(df.rows[rowIndex], index, value)
-		return nil
 
 	case int:
-		if rowIndex >= len(df.rows) {
-			df.rows = share.SetSliceValue(df.rows, rowIndex, make([]string, len(df.heads)))
-		}
-		df.rows[rowIndex] = share.SetSliceValue(df.rows[rowIndex], head, value)
-		return nil
+		index = head
 
 	default:
 		return fmt.Errorf("head type %T not supported", head)
 	}
+
+	if df.index != nil {
+		return df.setIndexedValue(rowIndex, index, value)
+	}
+	df.setValue(rowIndex, index, value)
+	return nil
+}
+
+func (df *DataFrame) getValue(rowIndex int, index int) (string, error) {
+	if rowIndex < 0 || rowIndex >= df.length {
+		return "", fmt.Errorf("row index %d out of range", rowIndex)
+	}
+	if index < 0 || index >= df.width() {
+		return "", fmt.Errorf("head index %d out of range", index)
+	}
+	return df.at(rowIndex, index), nil
+}
+
+func (df *DataFrame) setValue(rowIndex int, index int, value string) {
+	df.setCell(rowIndex, index, value)
+}
+
+// AppendRow 以表头为键追加一行，不存在的表头会按名称排序后追加到heads中，返回新行的索引
+func (df *DataFrame) AppendRow(row map[string]string) int {
+	var newHeads []string
+	for head := range row {
+		if _, ok := df.headIndexMap[head]; !ok {
+			newHeads = append(newHeads, head)
+		}
+	}
+	if len(newHeads) > 0 {
+		sort.Strings(newHeads)
+		df.heads = append(df.heads, newHeads...)
+		df.updateHeadIndexMap()
+		df.refreshIndex()
+	}
+
+	record := make([]string, len(df.heads))
+	for head, value := range row {
+		record[df.headIndexMap[head]] = value
+	}
+	df.appendRecord(record)
+	df.indexLastRow()
+	return df.length - 1
 }
 
-// AutoFillStruct sheet内容自动填充到结构体中，输入要求是一个结构体指针的切片的指针
+// AppendRowE 与 AppendRow 相同，设置了唯一索引时新行的键与已有的行重复会返回错误且不追加
+func (df *DataFrame) AppendRowE(row map[string]string) (int, error) {
+	if df.index != nil {
+		if err := df.checkUniqueKey(lo.Map(df.index.cols, func(col string, _ int) string {
+			return row[col]
+		})); err != nil {
+			return -1, err
+		}
+	}
+	return df.AppendRow(row), nil
+}
+
+// AppendRecordE 与 AppendRecord 相同，设置了唯一索引时新行的键与已有的行重复会返回错误且不追加
+func (df *DataFrame) AppendRecordE(record []string) (int, error) {
+	if df.index != nil {
+		if err := df.checkUniqueKey(lo.Map(df.index.positions, func(position int, _ int) string {
+			if position < len(record) {
+				return record[position]
+			}
+			return ""
+		})); err != nil {
+			return -1, err
+		}
+	}
+	return df.AppendRecord(record), nil
+}
+
+// AppendRecord 按列的顺序追加一行，返回新行的索引
+// record比表头长时与 AppendRow 一样扩展表头，新表头为excel的列名，与已有表头重名时加上 _2、_3 后缀
+func (df *DataFrame) AppendRecord(record []string) int {
+	if len(record) > len(df.heads) {
+		df.extendHeads(len(record))
+		df.refreshIndex()
+	}
+	df.appendRecord(record)
+	df.indexLastRow()
+	return df.length - 1
+}
+
+// extendHeads 把表头补齐到width个，新表头为excel的列名
+func (df *DataFrame) extendHeads(width int) {
+	for i := len(df.heads); i < width; i++ {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		head := name
+		for n := 2; lo.Contains(df.heads, head); n++ {
+			head = name + "_" + strconv.Itoa(n)
+		}
+		df.heads = append(df.heads, head)
+	}
+	df.updateHeadIndexMap()
+}
+
+// AutoFillStruct sheet内容自动填充到结构体中，输入要求是一个结构体或结构体指针的切片的指针
+// pd标签支持多个列名别名、default、required等选项，详见 fieldTag
 func (df *DataFrame) AutoFillStruct(dest any) error {
 	destVal := reflect.ValueOf(dest)
 	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
@@ -116,114 +228,141 @@ func (df *DataFrame) AutoFillStruct(dest any) error {
 	}
 
 	elemType := destVal.Elem().Type().Elem()
-	if elemType.Kind() != reflect.Ptr || elemType.Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("outSlice must be a slice of pointer to struct")
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("outSlice must be a slice of struct or pointer to struct")
 	}
 
 	for i := 0; i < df.GetLength(); i++ {
-		newStructPtr := reflect.New(elemType.Elem())
+		newStructPtr := reflect.New(structType)
 		newStruct := newStructPtr.Elem()
-		if err := df.fillStructFromSheet(i, newStruct, ""); err != nil {
+		if err := df.fillStructFromSheet(i, newStruct, nil); err != nil {
 			return err
 		}
-		destVal.Elem().Set(reflect.Append(destVal.Elem(), newStructPtr))
+		if isPtr {
+			destVal.Elem().Set(reflect.Append(destVal.Elem(), newStructPtr))
+		} else {
+			destVal.Elem().Set(reflect.Append(destVal.Elem(), newStruct))
+		}
 	}
 
 	return nil
 }
 
-// fillStructFromSheet 递归处理嵌套结构体的字段
-func (df *DataFrame) fillStructFromSheet(rowIndex int, val reflect.Value, prefix string) error {
+// fillStructFromSheet 递归处理嵌套结构体的字段，prefixes为嵌套结构体字段的所有列名
+func (df *DataFrame) fillStructFromSheet(rowIndex int, val reflect.Value, prefixes []string) error {
 	valType := val.Type()
 	for j := 0; j < valType.NumField(); j++ {
 		field := valType.Field(j)
 		fieldVal := val.Field(j)
 
 		if field.Anonymous {
-			if err := df.fillStructFromSheet(rowIndex, fieldVal, prefix); err != nil {
+			if fieldVal.Kind() == reflect.Ptr {
+				if fieldVal.IsNil() {
+					fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+				}
+				fieldVal = fieldVal.Elem()
+			}
+			if err := df.fillStructFromSheet(rowIndex, fieldVal, prefixes); err != nil {
 				return err
 			}
 			continue
 		}
 
-		columnName := field.Tag.Get("pd")
-		if columnName == "" {
-			continue
+		tag, ok, err := parseFieldTag(field)
+		if err != nil {
+			return err
 		}
-
-		// 处理带前缀的标签名
-		if prefix != "" {
-			columnName = prefix + "_" + columnName
+		if !ok {
+			continue
 		}
 
 		if !fieldVal.CanSet() {
 			return fmt.Errorf("cannot set field %s", field.Name)
 		}
 
-		switch fieldVal.Kind() {
-		case reflect.String:
-			value, _ := df.GetValueE(rowIndex, columnName)
-			fieldVal.SetString(value)
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			value, _ := df.GetValueE(rowIndex, columnName)
-			intVal, _ := strconv.ParseInt(value, 10, 64)
-			fieldVal.SetInt(intVal)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			value, _ := df.GetValueE(rowIndex, columnName)
-			uintVal, _ := strconv.ParseUint(value, 10, 64)
-			fieldVal.SetUint(uintVal)
-		case reflect.Float32, reflect.Float64:
-			value, _ := df.GetValueE(rowIndex, columnName)
-			floatVal, _ := strconv.ParseFloat(value, 64)
-			fieldVal.SetFloat(floatVal)
-		case reflect.Bool:
-			value, _ := df.GetValueE(rowIndex, columnName)
-			boolVal, _ := strconv.ParseBool(value)
-			fieldVal.SetBool(boolVal)
-		case reflect.Struct:
-			if fieldVal.Type() == reflect.TypeOf(time.Time{}) {
-				value, _ := df.GetValueE(rowIndex, columnName)
-				timeVal, _ := time.Parse(time.RFC3339, value)
-				fieldVal.Set(reflect.ValueOf(timeVal))
-			} else {
-				if err := df.fillStructFromSheet(rowIndex, fieldVal, columnName); err != nil {
-					return err
-				}
-				continue
-			}
-		case reflect.Ptr:
-			if fieldVal.Type().Elem().Kind() == reflect.Struct {
+		columnNames := tag.columnAliases(prefixes)
+		if isNestedStruct(field.Type) {
+			if fieldVal.Kind() == reflect.Ptr {
 				if fieldVal.IsNil() {
 					fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
 				}
-				if err := df.fillStructFromSheet(rowIndex, fieldVal.Elem(), columnName); err != nil {
-					return err
-				}
-				continue
+				fieldVal = fieldVal.Elem()
 			}
-		default:
-			return fmt.Errorf("unsupported field type: %v", fieldVal.Kind())
+			if err := df.fillStructFromSheet(rowIndex, fieldVal, columnNames); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, found := df.lookupValue(rowIndex, columnNames)
+		if value == "" && tag.hasDefault {
+			value = tag.defaultValue
+		}
+		if tag.required {
+			if !found {
+				return fmt.Errorf("cannot find head %s for required field %s", columnNames[0], field.Name)
+			}
+			if value == "" {
+				return fmt.Errorf("row %d: required field %s is empty", rowIndex, field.Name)
+			}
+		}
+
+		if err := setFieldValue(fieldVal, value); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// AutoFillSheet 结构体内容填充到excel表格中，会覆盖原本的内容，输入要求是一个结构体指针切片
+// lookupValue 按顺序查找第一个存在的列名并返回其值
+func (df *DataFrame) lookupValue(rowIndex int, columnNames []string) (string, bool) {
+	for _, columnName := range columnNames {
+		if _, ok := df.headIndexMap[columnName]; ok {
+			value, _ := df.GetValueE(rowIndex, columnName)
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// AutoFillSheet 结构体内容填充到excel表格中，会覆盖原本的内容，输入要求是一个结构体或结构体指针切片
+// 表头使用标签中的第一个列名，并按order选项排序
 func (df *DataFrame) AutoFillSheet(dest any) error {
-	df.SetRows([][]string{})
+	// 先清空表头再清空行，原来的列全部丢弃，重复使用的df不会保留之前的列数
 	df.SetHeads([]string{})
+	df.SetRows([][]string{})
 	destVal := reflect.ValueOf(dest)
 	if destVal.Kind() != reflect.Slice {
 		return fmt.Errorf("inputSlice must be a slice")
 	}
 
-	elemType := destVal.Type().Elem()
-	if elemType.Kind() != reflect.Ptr || elemType.Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("inputSlice must be a slice of pointer to struct")
+	structType := destVal.Type().Elem()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("inputSlice must be a slice of struct or pointer to struct")
+	}
+
+	heads, err := structHeads(structType)
+	if err != nil {
+		return err
 	}
+	df.SetHeads(heads)
 
 	for i := 0; i < destVal.Len(); i++ {
-		elemVal := destVal.Index(i).Elem()
+		elemVal := destVal.Index(i)
+		if elemVal.Kind() == reflect.Ptr {
+			if elemVal.IsNil() {
+				return fmt.Errorf("inputSlice element %d is nil", i)
+			}
+			elemVal = elemVal.Elem()
+		}
 		if err := df.fillStructFields(i, elemVal, ""); err != nil {
 			return err
 		}
@@ -240,55 +379,46 @@ func (df *DataFrame) fillStructFields(rowIndex int, val reflect.Value, prefix st
 		fieldVal := val.Field(j)
 
 		if field.Anonymous {
+			if fieldVal.Kind() == reflect.Ptr {
+				if fieldVal.IsNil() {
+					fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+				}
+				fieldVal = fieldVal.Elem()
+			}
 			if err := df.fillStructFields(rowIndex, fieldVal, prefix); err != nil {
 				return err
 			}
 			continue
 		}
 
-		columnName := field.Tag.Get("pd")
-		if columnName == "" {
-			continue
+		tag, ok, err := parseFieldTag(field)
+		if err != nil {
+			return err
 		}
-
-		// 处理带前缀的标签名
-		if prefix != "" {
-			columnName = prefix + "_" + columnName
+		if !ok {
+			continue
 		}
 
-		var inputVal string
-		switch fieldVal.Kind() {
-		case reflect.String:
-			inputVal = fieldVal.String()
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			inputVal = strconv.FormatInt(fieldVal.Int(), 10)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			inputVal = strconv.FormatUint(fieldVal.Uint(), 10)
-		case reflect.Float32, reflect.Float64:
-			inputVal = strconv.FormatFloat(fieldVal.Float(), 'f', -1, 64)
-		case reflect.Bool:
-			inputVal = strconv.FormatBool(fieldVal.Bool())
-		case reflect.Struct:
-			if fieldVal.Type() == reflect.TypeOf(time.Time{}) {
-				inputVal = fieldVal.Interface().(time.Time).Format(time.RFC3339)
-			} else {
-				if err := df.fillStructFields(rowIndex, fieldVal, columnName); err != nil {
-					return err
-				}
-				continue
-			}
-		case reflect.Ptr:
-			if fieldVal.Type().Elem().Kind() == reflect.Struct {
+		columnName := tag.columnNames(prefix)[0]
+		if isNestedStruct(field.Type) {
+			if fieldVal.Kind() == reflect.Ptr {
 				if fieldVal.IsNil() {
 					fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
 				}
-				if err := df.fillStructFields(rowIndex, fieldVal.Elem(), columnName); err != nil {
-					return err
-				}
-				continue
+				fieldVal = fieldVal.Elem()
+			}
+			if err := df.fillStructFields(rowIndex, fieldVal, columnName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var inputVal string
+		if !tag.omitEmpty || !fieldVal.IsZero() {
+			inputVal, err = formatFieldValue(fieldVal)
+			if err != nil {
+				return err
 			}
-		default:
-			return fmt.Errorf("unsupported field type: %v", fieldVal.Kind())
 		}
 
 		if err := df.SetValueE(rowIndex, columnName, inputVal); err != nil {
@@ -299,13 +429,22 @@ func (df *DataFrame) fillStructFields(rowIndex int, val reflect.Value, prefix st
 }
 
 func (df *DataFrame) GetLength() int {
-	return len(df.rows)
+	return df.length
 }
 
 func (df *DataFrame) UniqueRows() {
-	df.rows = lo.UniqBy(df.rows, func(slice []string) string {
-		return strings.Join(slice, "\x1F")
-	})
+	var rowIndexes []int
+	seen := map[string]bool{}
+	for i := 0; i < df.length; i++ {
+		key := strings.Join(df.record(i), "\x1F")
+		if !seen[key] {
+			seen[key] = true
+			rowIndexes = append(rowIndexes, i)
+		}
+	}
+	df.columns = df.pickRows(rowIndexes)
+	df.length = len(rowIndexes)
+	df.refreshIndex()
 }
 
 func (e *Excel) AppendSheet(dfs ...*DataFrame) {
@@ -321,70 +460,134 @@ func (e *Excel) AppendSheet(dfs ...*DataFrame) {
 	}
 }
 
-func (e *Excel) ReadExcelAllSheet(src string) error {
-	file, err := excelize.OpenFile(src)
+// ReadExcelAllSheet 读取所有sheet，加密的文件需要通过 WithReadPassword 传入密码
+func (e *Excel) ReadExcelAllSheet(src string, opts ...ReadOption) error {
+	file, err := openExcelFile(src, opts)
 	if err != nil {
 		return err
 	}
 	defer ioutils.CloseQuietly(file)
 
-	e.SheetNames = file.GetSheetList()
-	for _, sheetName := range e.SheetNames {
-		df := NewDataFrame(sheetName)
-		rows, err := file.GetRows(sheetName)
+	sheetNames := file.GetSheetList()
+	dataFramesMap := make(map[string]*DataFrame, len(sheetNames))
+	for _, sheetName := range sheetNames {
+		df, err := readSheet(file, sheetName)
 		if err != nil {
 			return err
 		}
-		if len(rows) > 0 {
-			df.SetHeads(rows[0])
-			df.SetRows(rows[1:])
-			df.updateHeadIndexMap()
-		}
-		e.DataFramesMap[sheetName] = df
+		dataFramesMap[sheetName] = df
 	}
 
+	e.SheetNames = sheetNames
+	e.DataFramesMap = dataFramesMap
+	e.activeSheet = file.GetSheetName(file.GetActiveSheetIndex())
+
 	return nil
 }
 
-func (e *Excel) SaveExcelAllSheet(dst string) error {
+// readSheet 读取一个sheet，第一行作为表头
+func readSheet(file *excelize.File, sheetName string) (*DataFrame, error) {
+	df := NewDataFrame(sheetName)
+	rows, err := file.GetRows(sheetName)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		df.SetHeads(rows[0])
+		df.SetRows(rows[1:])
+	}
+	return df, nil
+}
+
+// SaveExcelAllSheet 按 SheetNames 的顺序保存所有sheet，未设置活动sheet时第一个sheet为活动sheet
+// 不合法或重复的sheet名称会被自动修正，超过excel行数限制的sheet需要通过 WithSplitSheets 拆分，否则返回错误
+// 通过 WithPassword 加密文件，通过 WithSheetProtection 保护sheet
+func (e *Excel) SaveExcelAllSheet(dst string, opts ...SaveOption) error {
+	o := newSaveOptions(opts)
+
+	plans, err := e.planSheets(o)
+	if err != nil {
+		return err
+	}
+
 	file := excelize.NewFile()
+	defer ioutils.CloseQuietly(file)
 
-	for sheetName, df := range e.DataFramesMap {
-		index, err := file.NewSheet(sheetName)
-		if err != nil {
+	activeSheet := ""
+	for i, plan := range plans {
+		// 新文件自带Sheet1，直接重命名为第一个sheet，保证sheet顺序
+		if i == 0 {
+			if err := file.SetSheetName("Sheet1", plan.name); err != nil {
+				return err
+			}
+		} else if _, err := file.NewSheet(plan.name); err != nil {
 			return err
 		}
-		file.SetActiveSheet(index)
 
-		for i, head := range df.GetHeads() {
-			cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-
-			err = file.SetCellValue(sheetName, cell, head)
-			if err != nil {
+		if err := writeSheet(file, plan.name, plan.df.GetHeads(), plan.rows); err != nil {
+			return err
+		}
+		if o.protectSheets {
+			if err := protectSheet(file, plan.name, o.protectPassword); err != nil {
 				return err
 			}
 		}
 
-		for i, row := range df.GetRows() {
-			for j, cellValue := range row {
-				cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+		if activeSheet == "" && plan.source == e.activeSheet {
+			activeSheet = plan.name
+		}
+	}
 
-				err = file.SetCellValue(sheetName, cell, cellValue)
-				if err != nil {
-					return err
-				}
+	// 所有sheet写完后再添加规则，下拉列表的隐藏sheet排在最后
+	used := map[string]bool{}
+	for _, plan := range plans {
+		used[strings.ToLower(plan.name)] = true
+	}
+	o.listSheet = uniqueSheetName(dropdownListSheet, used)
+	for _, plan := range plans {
+		for _, rule := range o.sheetRules[plan.source] {
+			if err := rule(file, plan.name, plan.df.GetHeads(), len(plan.rows)); err != nil {
+				return fmt.Errorf("sheet %s: %w", plan.name, err)
 			}
 		}
 	}
 
-	if !lo.Contains(e.SheetNames, "Sheet1") {
-		err := file.DeleteSheet("Sheet1")
+	if activeSheet != "" {
+		index, err := file.GetSheetIndex(activeSheet)
 		if err != nil {
 			return err
 		}
+		if index >= 0 {
+			file.SetActiveSheet(index)
+		}
+	}
+
+	return file.SaveAs(dst, excelize.Options{Password: o.password})
+}
+
+// writeSheet 从A1开始写入表头和所有行
+func writeSheet(file *excelize.File, sheetName string, heads []string, rows [][]string) error {
+	for i, head := range heads {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+
+		err := file.SetCellValue(sheetName, cell, head)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, row := range rows {
+		for j, cellValue := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+
+			err := file.SetCellValue(sheetName, cell, cellValue)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return file.SaveAs(dst)
+	return nil
 }
 
 func (df *DataFrame) ReadCsv(src string) error {
@@ -419,13 +622,132 @@ func (df *DataFrame) SaveCsv(dst string) error {
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
-	if err := writer.Write(df.heads); err != nil {
+	// 表头补齐到与行相同的长度，使每一行的字段数量一致
+	if err := writer.Write(df.outputHeads()); err != nil {
 		return err
 	}
 
-	if err := writer.WriteAll(df.rows); err != nil {
+	for i := 0; i < df.length; i++ {
+		if err := writer.Write(df.record(i)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadJson 读取json文件，要求内容为对象数组，表头按第一次出现的键顺序排列，非字符串的值会转为字符串
+func (df *DataFrame) ReadJson(src string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
 		return err
 	}
 
+	var records []json.RawMessage
+	if err := json.Unmarshal(data, &records); err != nil {
+		return err
+	}
+
+	df.SetHeads([]string{})
+	df.SetRows([][]string{})
+	for i, record := range records {
+		keys, values, err := decodeJsonObject(record)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i, err)
+		}
+		for j, key := range keys {
+			if err := df.SetValueE(i, key, values[j]); err != nil {
+				return err
+			}
+		}
+		// 空对象也占一行
+		df.grow(0, i+1)
+	}
+
 	return nil
 }
+
+// decodeJsonObject 按原始顺序解析json对象的键值
+func decodeJsonObject(data []byte) ([]string, []string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, nil, fmt.Errorf("json record must be an object")
+	}
+
+	var keys, values []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := token.(string)
+
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+
+		var strVal string
+		switch value := value.(type) {
+		case nil:
+		case string:
+			strVal = value
+		case json.Number:
+			strVal = value.String()
+		case bool:
+			strVal = strconv.FormatBool(value)
+		default:
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, nil, err
+			}
+			strVal = string(raw)
+		}
+
+		keys = append(keys, key)
+		values = append(values, strVal)
+	}
+
+	return keys, values, nil
+}
+
+// SaveJson 保存为json文件，每一行为一个以表头为键的对象，键按表头顺序输出，没有表头的列以excel的列名为键
+func (df *DataFrame) SaveJson(dst string) error {
+	// 没有表头的列使用excel的列名作为键，例如 C
+	heads := df.outputHeads()
+	for j := len(df.heads); j < len(heads); j++ {
+		heads[j], _ = excelize.ColumnNumberToName(j + 1)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i := 0; i < df.length; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for j, head := range heads {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			key, _ := json.Marshal(head)
+			val, _ := json.Marshal(df.at(i, j))
+			buf.Write(key)
+			buf.WriteString(": ")
+			buf.Write(val)
+		}
+		buf.WriteString("}")
+	}
+	if df.length > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	return os.WriteFile(dst, buf.Bytes(), 0666)
+}
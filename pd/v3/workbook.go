@@ -0,0 +1,127 @@
+package pd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// sheetTag 解析后的sheet标签，格式为 `sheet:"用户,required"`
+type sheetTag struct {
+	name     string
+	required bool
+}
+
+func parseSheetTag(field reflect.StructField) (sheetTag, bool, error) {
+	var tag sheetTag
+
+	raw := field.Tag.Get("sheet")
+	if raw == "" || raw == "-" {
+		return tag, false, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	tag.name = strings.TrimSpace(parts[0])
+	if tag.name == "" {
+		return tag, false, fmt.Errorf("field %s has no sheet name in tag %q", field.Name, raw)
+	}
+
+	for _, option := range parts[1:] {
+		switch option = strings.TrimSpace(option); option {
+		case "required":
+			tag.required = true
+		case "":
+		default:
+			return tag, false, fmt.Errorf("field %s has unknown sheet tag option %q", field.Name, option)
+		}
+	}
+
+	if field.Type.Kind() != reflect.Slice {
+		return tag, false, fmt.Errorf("field %s with sheet tag must be a slice", field.Name)
+	}
+
+	return tag, true, nil
+}
+
+// AutoFillWorkbook 将多个sheet的内容填充到结构体中，输入要求是一个结构体指针
+// 结构体中带有sheet标签的切片字段会由对应sheet填充，例如 Users []*User `sheet:"用户"`
+// 标签带有required选项时，对应sheet不存在会返回错误
+func (e *Excel) AutoFillWorkbook(dest any) error {
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a pointer to struct")
+	}
+
+	val := destVal.Elem()
+	valType := val.Type()
+	for i := 0; i < valType.NumField(); i++ {
+		field := valType.Field(i)
+
+		tag, ok, err := parseSheetTag(field)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		df, ok := e.DataFramesMap[tag.name]
+		if !ok {
+			if tag.required {
+				return fmt.Errorf("cannot find required sheet %s", tag.name)
+			}
+			continue
+		}
+
+		fieldVal := val.Field(i)
+		if !fieldVal.CanSet() {
+			return fmt.Errorf("cannot set field %s", field.Name)
+		}
+
+		items := reflect.New(field.Type)
+		if err := df.AutoFillStruct(items.Interface()); err != nil {
+			return fmt.Errorf("sheet %s: %w", tag.name, err)
+		}
+		fieldVal.Set(items.Elem())
+	}
+
+	return nil
+}
+
+// FromWorkbookStruct 将结构体中带有sheet标签的切片字段写入对应的sheet，输入要求是一个结构体或结构体指针
+// sheet按字段顺序追加，已存在的同名sheet会被覆盖
+func (e *Excel) FromWorkbookStruct(src any) error {
+	srcVal := reflect.ValueOf(src)
+	if srcVal.Kind() == reflect.Ptr {
+		srcVal = srcVal.Elem()
+	}
+	if srcVal.Kind() != reflect.Struct {
+		return fmt.Errorf("src must be a struct or pointer to struct")
+	}
+
+	srcType := srcVal.Type()
+	for i := 0; i < srcType.NumField(); i++ {
+		field := srcType.Field(i)
+
+		tag, ok, err := parseSheetTag(field)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		fieldVal := srcVal.Field(i)
+		if !fieldVal.CanInterface() {
+			return fmt.Errorf("cannot read field %s", field.Name)
+		}
+
+		df := NewDataFrame(tag.name)
+		if err := df.AutoFillSheet(fieldVal.Interface()); err != nil {
+			return fmt.Errorf("sheet %s: %w", tag.name, err)
+		}
+		e.AppendSheet(df)
+	}
+
+	return nil
+}
@@ -118,8 +118,10 @@ func (df *DataFrame) UniqueRows() {
 	})
 }
 
-func (df *DataFrame) ReadExcel(src string) error {
-	file, err := excelize.OpenFile(src)
+// ReadExcel 读取xlsx文件，加密的文件通过 WithReadPassword 传入密码
+func (df *DataFrame) ReadExcel(src string, opts ...ReadOption) error {
+	o := newReadOptions(opts)
+	file, err := excelize.OpenFile(src, excelize.Options{Password: o.password})
 	if err != nil {
 		return err
 	}